@@ -58,6 +58,9 @@ func (CLI) PrintTable(header []string, values [][]string) {
 	tbl.Print()
 }
 
+// PrintObject does nothing, since the tables and lines already print the results.
+func (CLI) PrintObject(v interface{}) {}
+
 // PrintColorizedLine print a colorized line.
 func (CLI) PrintColorizedLine(title, content string, level ergo.MessageLevel) {
 	var err error
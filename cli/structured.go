@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/beatlabs/ergo"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OutputTable prints colorized text and tables.
+	OutputTable = "table"
+	// OutputJSON prints the results as JSON.
+	OutputJSON = "json"
+	// OutputYAML prints the results as YAML.
+	OutputYAML = "yaml"
+	// OutputCSV prints the tables as CSV.
+	OutputCSV = "csv"
+)
+
+// StructuredCLI prints the results in a machine-readable format to the output and the messages
+// as plain text to the error output, so that the output can be parsed. JSON and YAML print the
+// objects, CSV prints the tables.
+type StructuredCLI struct {
+	format string
+	out    io.Writer
+	errOut io.Writer
+	in     *bufio.Reader
+	// csvHeader is the header of the first table printed as CSV.
+	csvHeader []string
+}
+
+// NewStructuredCLI initialize and return a new StructuredCLI object for the json, yaml or csv format.
+func NewStructuredCLI(format string, in io.Reader, out, errOut io.Writer) (*StructuredCLI, error) {
+	switch format {
+	case OutputJSON, OutputYAML, OutputCSV:
+	default:
+		return nil, fmt.Errorf("invalid output format %q", format)
+	}
+	return &StructuredCLI{format: format, out: out, errOut: errOut, in: bufio.NewReader(in)}, nil
+}
+
+// PrintTable prints the table as CSV, for the csv format. Only the first table, and the tables
+// with the same columns, are printed to the output, so that it is a single CSV. The other tables
+// are printed to the error output.
+func (c *StructuredCLI) PrintTable(header []string, values [][]string) {
+	if c.format != OutputCSV {
+		return
+	}
+
+	w := csv.NewWriter(c.out)
+	switch {
+	case c.csvHeader == nil:
+		c.csvHeader = header
+		_ = w.Write(header)
+	case !equalStrings(c.csvHeader, header):
+		w = csv.NewWriter(c.errOut)
+		_ = w.Write(header)
+	}
+	_ = w.WriteAll(values)
+	if err := w.Error(); err != nil {
+		fmt.Fprintln(c.errOut, err)
+	}
+}
+
+// equalStrings reports whether the slices have the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PrintObject prints the object as JSON or YAML, for the json and yaml formats.
+func (c *StructuredCLI) PrintObject(v interface{}) {
+	var err error
+	switch c.format {
+	case OutputJSON:
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(v)
+	case OutputYAML:
+		var data []byte
+		data, err = yaml.Marshal(v)
+		if err == nil {
+			_, err = c.out.Write(append([]byte("---\n"), data...))
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(c.errOut, err)
+	}
+}
+
+// PrintColorizedLine prints the line without colors to the error output.
+func (c *StructuredCLI) PrintColorizedLine(title, content string, level ergo.MessageLevel) {
+	fmt.Fprintln(c.errOut, title+content)
+}
+
+// PrintLine prints the line to the error output.
+func (c *StructuredCLI) PrintLine(content ...interface{}) {
+	fmt.Fprintln(c.errOut, content...)
+}
+
+// Confirmation asks for confirmation on the error output and returns true if the user accepts.
+func (c *StructuredCLI) Confirmation(actionText, cancellationMessage, successMessage string) (bool, error) {
+	fmt.Fprint(c.errOut, actionText, "? ", confirmationText)
+
+	input, err := c.Input()
+	if err != nil {
+		return false, err
+	}
+
+	if !inSlice(input, confirmationResponse) {
+		if cancellationMessage != "" {
+			fmt.Fprintln(c.errOut, cancellationMessage)
+		}
+		return false, nil
+	}
+	if successMessage != "" {
+		fmt.Fprintln(c.errOut, successMessage)
+	}
+	return true, nil
+}
+
+// Input reads a line from the input and returns it.
+func (c *StructuredCLI) Input() (string, error) {
+	input, err := c.in.ReadString('\n')
+	if err != nil && (err != io.EOF || input == "") {
+		return "", err
+	}
+	return strings.TrimRight(input, "\r\n"), nil
+}
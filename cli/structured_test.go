@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type object struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+func TestStructuredCLIShouldPrintInTheFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: OutputJSON, want: "{\n  \"name\": \"ergo\"\n}\n"},
+		{format: OutputYAML, want: "---\nname: ergo\n"},
+		{format: OutputCSV, want: "Branch,Behind\nrelease-gr,\"1,2\"\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			c, err := NewStructuredCLI(test.format, strings.NewReader(""), &out, &errOut)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.PrintLine("Deploying", "release-gr")
+			c.PrintTable([]string{"Branch", "Behind"}, [][]string{{"release-gr", "1,2"}})
+			c.PrintObject(&object{Name: "ergo"})
+
+			if out.String() != test.want {
+				t.Errorf("got output %q; want %q", out.String(), test.want)
+			}
+			if errOut.String() != "Deploying release-gr\n" {
+				t.Errorf("unexpected error output %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestStructuredCLIShouldPrintASingleCSV(t *testing.T) {
+	var out, errOut bytes.Buffer
+	c, err := NewStructuredCLI(OutputCSV, strings.NewReader(""), &out, &errOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.PrintTable([]string{"Branch", "Start Time"}, [][]string{{"release-gr", "10:00 UTC"}})
+	c.PrintTable([]string{"Check", "State", "URL"}, [][]string{{"build", "failure", "url"}})
+	c.PrintTable([]string{"Branch", "Start Time"}, [][]string{{"release-mx", "10:05 UTC"}})
+
+	if want := "Branch,Start Time\nrelease-gr,10:00 UTC\nrelease-mx,10:05 UTC\n"; out.String() != want {
+		t.Errorf("got output %q; want %q", out.String(), want)
+	}
+	if want := "Check,State,URL\nbuild,failure,url\n"; errOut.String() != want {
+		t.Errorf("got error output %q; want %q", errOut.String(), want)
+	}
+}
+
+func TestNewStructuredCLIShouldReturnErrorForUnknownFormat(t *testing.T) {
+	if _, err := NewStructuredCLI("xml", strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Error("NewStructuredCLI should return error for an unknown format")
+	}
+}
+
+func TestStructuredCLIConfirmationShouldReadTheInput(t *testing.T) {
+	var errOut bytes.Buffer
+	c, err := NewStructuredCLI(OutputJSON, strings.NewReader("y\nn\n"), &bytes.Buffer{}, &errOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, _ := c.Confirmation("Deploy", "", ""); !ok {
+		t.Error("expected confirmation for y")
+	}
+	if ok, _ := c.Confirmation("Deploy", "No deployment", ""); ok {
+		t.Error("expected no confirmation for n")
+	}
+	if !strings.Contains(errOut.String(), "Deploy? [y/N]: ") {
+		t.Errorf("unexpected prompt %q", errOut.String())
+	}
+}
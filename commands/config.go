@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+	"github.com/beatlabs/ergo/config/viper"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// configCheck is the result of a check of the config run by config validate.
+type configCheck struct {
+	Check   string `json:"check" yaml:"check"`
+	Subject string `json:"subject" yaml:"subject"`
+	Result  string `json:"result" yaml:"result"`
+	OK      bool   `json:"ok" yaml:"ok"`
+}
+
+// tokenScoper is implemented by the hosts which can report the scopes of the access token.
+type tokenScoper interface {
+	TokenScopes(ctx context.Context) ([]string, bool, error)
+}
+
+// defineConfigCommand defines the config command and its init, validate and show subcommands.
+func defineConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Create, validate or show the config",
+		Long:  "Create the config with a wizard, check it for mistakes or show the effective settings and their sources",
+	}
+	configCmd.AddCommand(defineConfigInitCommand())
+	configCmd.AddCommand(defineConfigValidateCommand())
+	configCmd.AddCommand(defineConfigShowCommand())
+	return configCmd
+}
+
+// defineConfigInitCommand defines the config init command.
+func defineConfigInitCommand() *cobra.Command {
+	var file string
+
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create the config file with an interactive wizard",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := homedir.Expand(file)
+			if err != nil {
+				return err
+			}
+			return viper.NewInitWizard(cli.NewCLI(), vipOpts.Path).Run(path)
+		},
+	}
+	initCmd.Flags().StringVar(&file, "file", "~/.ergo.yaml", "The config file to write, e.g. .ergo.yaml for the config of the repository")
+
+	return initCmd
+}
+
+// defineConfigValidateCommand defines the config validate command.
+func defineConfigValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config for unknown keys, missing branches and token scopes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			prt, err := newPrinter()
+			if err != nil {
+				return err
+			}
+
+			checks := validateConfig(ctx)
+			printConfigChecks(prt, checks)
+
+			var failed int
+			for _, check := range checks {
+				if !check.OK {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d problems found in the config", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// defineConfigShowCommand defines the config show command.
+func defineConfigShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective config with the source of every value",
+		Long:  "Prints the settings merged from the config files, the profile and the flags, with the secrets masked",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prt, err := newPrinter()
+			if err != nil {
+				return err
+			}
+			// resolve the access token to show its source, the config may not be valid yet
+			if o, err := vipOpts.GetConfig(); err == nil {
+				opts = o
+			}
+
+			settings := vipOpts.Settings()
+			body := make([][]string, 0, len(settings))
+			for _, setting := range settings {
+				body = append(body, []string{setting.Key, setting.Value, setting.Source})
+			}
+			prt.PrintTable([]string{"Key", "Value", "Source"}, body)
+			prt.PrintObject(settings)
+			return nil
+		},
+	}
+}
+
+// validateConfig checks the keys of the config, the mandatory settings, the existence of the
+// branches and the scopes of the access token.
+func validateConfig(ctx context.Context) []configCheck {
+	var checks []configCheck
+	for _, key := range viper.UnknownKeys() {
+		checks = append(checks, configCheck{Check: "key", Subject: key, Result: "unknown key"})
+	}
+
+	o, err := vipOpts.GetConfig()
+	if err != nil {
+		return append(checks, configCheck{Check: "settings", Result: err.Error()})
+	}
+	opts = o
+	checks = append(checks, configCheck{Check: "settings", Result: "ok", OK: true})
+
+	host, err := newHost(ctx)
+	if err != nil {
+		return append(checks, configCheck{Check: "host", Subject: opts.Host, Result: err.Error()})
+	}
+	checks = append(checks, validateBranches(ctx, host)...)
+
+	if scoper, ok := host.(tokenScoper); ok && opts.GithubApp == nil {
+		checks = append(checks, validateTokenScopes(ctx, scoper))
+	}
+	return checks
+}
+
+// validateBranches checks that the base, status and release branches exist.
+func validateBranches(ctx context.Context, host ergo.Host) []configCheck {
+	var checks []configCheck
+	seen := make(map[string]bool)
+	branches := append([]string{opts.BaseBranch}, opts.Branches...)
+	for _, branch := range append(branches, opts.ReleaseBranches...) {
+		if branch == "" || seen[branch] {
+			continue
+		}
+		seen[branch] = true
+
+		check := configCheck{Check: "branch", Subject: branch, Result: "ok", OK: true}
+		if _, err := host.GetRef(ctx, branch); err != nil {
+			check.Result, check.OK = fmt.Sprintf("not found: %v", err), false
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// validateTokenScopes checks that the access token can read and write the repository.
+func validateTokenScopes(ctx context.Context, scoper tokenScoper) configCheck {
+	check := configCheck{Check: "token scopes", Subject: "access token"}
+
+	scopes, reported, err := scoper.TokenScopes(ctx)
+	switch {
+	case err != nil:
+		check.Result = err.Error()
+	case !reported:
+		check.Result, check.OK = "no scopes reported, the permissions of fine-grained tokens are not checked", true
+	case hasScope(scopes, "repo") || hasScope(scopes, "public_repo"):
+		check.Result, check.OK = strings.Join(scopes, ", "), true
+	default:
+		check.Result = fmt.Sprintf("missing the repo scope, found %q", strings.Join(scopes, ", "))
+	}
+	return check
+}
+
+// hasScope reports whether the scope is one of the scopes.
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// printConfigChecks prints the checks of the config.
+func printConfigChecks(prt ergo.CLI, checks []configCheck) {
+	body := make([][]string, 0, len(checks))
+	for _, check := range checks {
+		status := "OK"
+		if !check.OK {
+			status = "FAILED"
+		}
+		body = append(body, []string{check.Check, check.Subject, status, check.Result})
+	}
+	prt.PrintTable([]string{"Check", "Subject", "Status", "Result"}, body)
+	prt.PrintObject(checks)
+}
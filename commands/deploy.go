@@ -2,10 +2,15 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/beatlabs/ergo/cli"
-	"github.com/beatlabs/ergo/github"
 	"github.com/beatlabs/ergo/release"
+	ergoTime "github.com/beatlabs/ergo/time"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +23,14 @@ func defineDeployCommand() *cobra.Command {
 		branchesString  string
 		skipConfirm     bool
 		publishDraft    bool
+		resume          bool
+		checksTimeout   string
+		dryRun          bool
+		releaseName     string
+		only            string
+		skip            string
+		at              string
+		overrideFreeze  string
 	)
 
 	deployCmd := &cobra.Command{
@@ -27,40 +40,179 @@ func defineDeployCommand() *cobra.Command {
 	}
 
 	deployCmd.Flags().StringVar(&releaseOffset, "releaseOffset", "1m", "Duration to wait before the first release ('5m', '1h25m', '30s')")
+	deployCmd.Flags().StringVar(&at, "at", "", "Start time of the first release ('2026-10-20T14:00'), in release.schedule.time-zone or the local time zone")
 	deployCmd.Flags().StringVar(&releaseInterval, "releaseInterval", "25m", "Duration to wait between releases. ('5m', '1h25m', '30s')\n"+
 		"You can do a non-linear interval by supplying more values: ('15m,10m,5m,5m,5m')")
 	deployCmd.Flags().BoolVar(&allowForcePush, "force", false, "Allow force push if deploy branch has diverged from base")
 	deployCmd.Flags().StringVar(&branchesString, "branches", "", "Comma separated list of branches")
 	deployCmd.Flags().BoolVar(&skipConfirm, "skip-confirmation", false, "Create the draft without asking for user confirmation.")
 	deployCmd.Flags().BoolVar(&publishDraft, "publish-draft", false, "Publish the latest draft release before deployment.")
+	deployCmd.Flags().BoolVar(&resume, "resume", false, "Resume an interrupted deployment from the first branch which was not triggered.")
+	deployCmd.Flags().StringVar(&releaseName, "release", "", "Tag or ID of the release to deploy, instead of the latest release.")
+	deployCmd.Flags().StringVar(&only, "only", "", "Comma separated waves of the rollout plan, or branches, to deploy in this run")
+	deployCmd.Flags().StringVar(&skip, "skip", "", "Comma separated waves of the rollout plan, or branches, not to deploy in this run")
+	deployCmd.Flags().StringVar(&overrideFreeze, "override-freeze", "", "Deploy even if a branch is planned in a freeze window, recording the reason in the release body.")
+	deployCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the timeline and the changes of the deployment without making them.")
+
+	deployCmd.Flags().StringVar(&checksTimeout, "checks-timeout", "", "Wait up to this duration for the CI checks of each deployed branch "+
+		"to pass before deploying the next one ('30m'). Overrides release.on-deploy.checks.timeout, '0' disables the gate.")
 
 	deployCmd.RunE = func(cmd *cobra.Command, args []string) error {
-		return defineDeployCommandRun(releaseInterval, releaseOffset, branchesString, allowForcePush, skipConfirm, publishDraft)
+		if checksTimeout != "" {
+			timeout, err := time.ParseDuration(checksTimeout)
+			if err != nil {
+				return fmt.Errorf("error parsing checks timeout: %w", err)
+			}
+			opts.ChecksTimeout = timeout
+		}
+		if resume && (releaseName != "" || only != "" || skip != "" || at != "") {
+			return errors.New("--release, --only, --skip and --at can not be combined with --resume")
+		}
+		if at != "" && cmd.Flags().Changed("releaseOffset") {
+			return errors.New("--at can not be combined with --releaseOffset")
+		}
+		return defineDeployCommandRun(releaseInterval, releaseOffset, branchesString, releaseName, only, skip, at, overrideFreeze,
+			allowForcePush, skipConfirm, publishDraft, resume, dryRun)
 	}
 
 	return deployCmd
 }
 
 // defineDeployCommandRun defines the deploy command run actions.
-func defineDeployCommandRun(releaseInterval, releaseOffset, branchesString string, allowForcePush, skipConfirm, publishDraft bool) error {
+func defineDeployCommandRun(
+	releaseInterval, releaseOffset, branchesString, releaseName, only, skip, at, overrideFreeze string,
+	allowForcePush, skipConfirm, publishDraft, resume, dryRun bool,
+) error {
 	ctx := context.Background()
 
 	if branchesString != "" {
 		vipOpts.SetReleaseBranches(branchesString)
 	}
 
-	printer := cli.NewCLI()
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	waves, releaseBranches, err := rolloutWaves(branchesString != "", only, skip)
+	if err != nil {
+		return err
+	}
+
+	var startTime time.Time
+	if at != "" {
+		if startTime, err = ergoTime.ParseWallClock(at, opts.ScheduleLocation); err != nil {
+			return fmt.Errorf("error parsing --at: %w", err)
+		}
+	}
+
+	freeze, err := newFreeze(overrideFreeze)
+	if err != nil {
+		return err
+	}
 
-	githubClient := github.NewGithubClient(ctx, opts.AccToken)
-	host := github.NewRepositoryClient(opts.Organization, opts.RepoName, githubClient)
+	host, err := newHost(ctx)
+	if err != nil {
+		return err
+	}
 
-	return release.NewDeploy(
+	deploy := release.NewDeploy(
 		printer,
 		host,
 		opts.BaseBranch,
 		opts.ReleaseBodyFind,
 		opts.ReleaseBodyReplace,
-		opts.ReleaseBranches,
+		releaseBranches,
 		opts.ReleaseBodyBranches,
-	).Do(ctx, releaseInterval, releaseOffset, allowForcePush, skipConfirm, publishDraft)
+	)
+	deploy.SetStatePath(deployStatePath(host.GetRepoName()))
+	deploy.SetChecksGate(opts.ChecksPollInterval, opts.ChecksTimeout)
+	deploy.SetHealthChecks(healthChecks(), &http.Client{Timeout: time.Minute})
+	deploy.SetRelease(releaseName)
+	if waves != nil {
+		deploy.SetWaves(waves)
+	}
+	deploy.SetStartTime(startTime)
+	deploy.SetScheduleLocation(opts.ScheduleLocation)
+	deploy.SetBoardLocations(opts.BoardLocations)
+	deploy.SetFreeze(freeze)
+	if dryRun {
+		deploy.SetDryRun()
+	}
+
+	if resume {
+		return deploy.Resume(ctx, skipConfirm)
+	}
+
+	return deploy.Do(ctx, releaseInterval, releaseOffset, allowForcePush, skipConfirm, publishDraft)
+}
+
+// rolloutWaves returns the waves of the rollout plan selected with --only and --skip, or nil if
+// there is no plan or the branches are given with --branches, and the release branches, which
+// are selected with --only and --skip when there are no waves.
+func rolloutWaves(branchesGiven bool, only, skip string) ([]release.Wave, []string, error) {
+	onlyNames, skipNames := splitNames(only), splitNames(skip)
+
+	if len(opts.RolloutWaves) > 0 && !branchesGiven {
+		waves := make([]release.Wave, 0, len(opts.RolloutWaves))
+		for _, w := range opts.RolloutWaves {
+			waves = append(waves, release.Wave{Name: w.Name, Branches: w.Branches, Delay: w.Delay, Confirm: w.Confirm})
+		}
+		selected, err := release.SelectWaves(waves, onlyNames, skipNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		return selected, opts.ReleaseBranches, nil
+	}
+	if len(onlyNames) == 0 && len(skipNames) == 0 {
+		return nil, opts.ReleaseBranches, nil
+	}
+
+	waves := make([]release.Wave, 0, len(opts.ReleaseBranches))
+	for _, branch := range opts.ReleaseBranches {
+		waves = append(waves, release.Wave{Name: branch, Branches: []string{branch}})
+	}
+	selected, err := release.SelectWaves(waves, onlyNames, skipNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	branches := make([]string, 0, len(selected))
+	for _, wave := range selected {
+		branches = append(branches, wave.Branches...)
+	}
+	return nil, branches, nil
+}
+
+// splitNames splits the comma separated names, ignoring the empty ones.
+func splitNames(names string) []string {
+	var split []string
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			split = append(split, name)
+		}
+	}
+	return split
+}
+
+// healthChecks returns the configured health checks of the release branches.
+func healthChecks() map[string]*release.HealthCheck {
+	checks := make(map[string]*release.HealthCheck, len(opts.HealthChecks))
+	for branch, c := range opts.HealthChecks {
+		checks[branch] = &release.HealthCheck{
+			URL:            c.URL,
+			ExpectedStatus: c.ExpectedStatus,
+			JSONField:      c.JSONField,
+			ExpectedValue:  c.ExpectedValue,
+			PollInterval:   c.PollInterval,
+			Timeout:        c.Timeout,
+			Rollback:       c.Rollback,
+		}
+	}
+	return checks
+}
+
+// deployStatePath returns the path of the deploy state file of the repo.
+func deployStatePath(repoName string) string {
+	name := fmt.Sprintf(".ergo-deploy-%s.json", strings.ReplaceAll(repoName, "/", "-"))
+	return filepath.Join(opts.DeployStateDir, name)
 }
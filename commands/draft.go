@@ -2,12 +2,15 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/template"
 
+	"github.com/beatlabs/ergo"
 	"github.com/beatlabs/ergo/release"
 
-	"github.com/beatlabs/ergo/cli"
-
-	"github.com/beatlabs/ergo/github"
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +24,11 @@ func defineDraftCommand() *cobra.Command {
 		major            bool
 		suffix           string
 		skipConfirmation bool
+		pullRequests     bool
+		auto             bool
+		dryRun           bool
+		publish          bool
+		overrideFreeze   string
 	)
 
 	draftCmd := &cobra.Command{
@@ -30,34 +38,67 @@ func defineDraftCommand() *cobra.Command {
 	}
 
 	draftCmd.Flags().StringVar(&releaseName, "releaseName", "", "Name for the release. If empty the tag name will be used")
-	draftCmd.Flags().StringVar(&releaseTag, "releaseTag", "", "Tag for the release. If empty, current date in YYYY.MM.DD will be used")
+	draftCmd.Flags().StringVar(&releaseTag, "releaseTag", "", "Tag for the release. If empty, the next semantic version or the configured calendar version will be used")
 	draftCmd.Flags().BoolVar(&minor, "minor", false, "The minor part of the tag.")
 	draftCmd.Flags().BoolVar(&major, "major", false, "The major part of the tag.")
 	draftCmd.Flags().StringVar(&suffix, "suffix", "", "The suffix of the tag.")
 	draftCmd.Flags().StringVar(&branchesString, "branches", "", "Comma separated list of branches")
 	draftCmd.Flags().BoolVar(&skipConfirmation, "skip-confirmation", false, "Create the draft without asking for user confirmation.")
+	draftCmd.Flags().BoolVar(&auto, "auto", false, "Infer the version increase from the conventional commits and group the commits by type.")
+	draftCmd.Flags().BoolVar(&pullRequests, "pull-requests", false, "List the merged pull requests of the commits, grouped by label, instead of the commits.")
+	draftCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the release body and the draft without creating it.")
+	draftCmd.Flags().BoolVar(&publish, "publish", false, "Publish the draft once created, unless the release branches are in a freeze window.")
+	draftCmd.Flags().StringVar(&overrideFreeze, "override-freeze", "", "Publish even if a release branch is in a freeze window, recording the reason in the release body.")
 
 	draftCmd.RunE = func(cmd *cobra.Command, args []string) error {
-		return defineDraftCommandRun(releaseName, releaseTag, suffix, branchesString, major, minor, skipConfirmation)
+		if auto && (major || minor) {
+			return errors.New("--auto can not be combined with --major or --minor")
+		}
+		if auto && pullRequests {
+			return errors.New("--auto can not be combined with --pull-requests")
+		}
+		if overrideFreeze != "" && !publish {
+			return errors.New("--override-freeze requires --publish")
+		}
+		return defineDraftCommandRun(releaseName, releaseTag, suffix, branchesString, overrideFreeze,
+			major, minor, skipConfirmation, pullRequests, auto, dryRun, publish)
 	}
 
 	return draftCmd
 }
 
 // defineDraftCommandRun defines the draft command run actions.
-func defineDraftCommandRun(releaseName, releaseTag, suffix, branchesString string, major, minor, skipConfirmation bool) error {
+func defineDraftCommandRun(
+	releaseName, releaseTag, suffix, branchesString, overrideFreeze string,
+	major, minor, skipConfirmation, pullRequests, auto, dryRun, publish bool,
+) error {
 	ctx := context.Background()
 
 	if branchesString != "" {
 		vipOpts.SetReleaseBranches(branchesString)
 	}
 
-	printer := cli.NewCLI()
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	host, err := newHost(ctx)
+	if err != nil {
+		return err
+	}
 
-	githubClient := github.NewGithubClient(ctx, opts.AccToken)
-	host := github.NewRepositoryClient(opts.Organization, opts.RepoName, githubClient)
+	// the diff inferring the version increase is the one of the release body
+	var diff []*ergo.StatusReport
+	if auto {
+		diff, err = host.DiffCommits(ctx, opts.ReleaseBranches, opts.BaseBranch)
+		if err != nil {
+			return err
+		}
+		major, minor = release.InferBump(diff)
+	}
 
-	version, err := release.NewVersion(host, opts.BaseBranch).NextVersion(ctx, releaseTag, suffix, major, minor)
+	version, err := newVersion(host).NextVersion(ctx, releaseTag, suffix, major, minor)
 	if err != nil {
 		return err
 	}
@@ -66,12 +107,56 @@ func defineDraftCommandRun(releaseName, releaseTag, suffix, branchesString strin
 		releaseName = version.Name
 	}
 
-	return release.NewDraft(
+	draft := release.NewDraft(
 		printer,
 		host,
 		opts.BaseBranch,
 		opts.ReleaseBodyPrefix,
 		opts.ReleaseBranches,
 		opts.ReleaseBodyBranches,
-	).Create(ctx, releaseName, version.Name, skipConfirmation)
+	)
+
+	if auto {
+		draft.SetConventionalCommits()
+		draft.SetDiff(diff)
+	}
+
+	if dryRun {
+		draft.SetDryRun()
+	}
+
+	if publish {
+		freeze, errFreeze := newFreeze(overrideFreeze)
+		if errFreeze != nil {
+			return errFreeze
+		}
+		draft.SetPublish(freeze)
+	}
+
+	if pullRequests {
+		draft.SetPullRequestNotes(opts.PullRequestLabels)
+	}
+
+	if opts.ReleaseBodyTemplate != "" {
+		bodyTemplate, errTemplate := releaseBodyTemplate(opts.ReleaseBodyTemplate)
+		if errTemplate != nil {
+			return errTemplate
+		}
+		draft.SetBodyTemplate(bodyTemplate)
+	}
+
+	return draft.Create(ctx, releaseName, version.Name, skipConfirmation)
+}
+
+// releaseBodyTemplate reads and parses the release body template file.
+func releaseBodyTemplate(path string) (*template.Template, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading release body template: %w", err)
+	}
+	return release.ParseBodyTemplate(string(text))
 }
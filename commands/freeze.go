@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beatlabs/ergo/config"
+	"github.com/beatlabs/ergo/release"
+	"github.com/mitchellh/go-homedir"
+)
+
+// icalYears is how many years ahead the repeating iCal events without an end are frozen.
+const icalYears = 2
+
+// newFreeze returns the freeze of the configured freeze calendars, overridden with the reason if
+// it is not empty.
+func newFreeze(overrideReason string) (*release.Freeze, error) {
+	calendar, err := freezeCalendar(opts.FreezeCalendar)
+	if err != nil {
+		return nil, err
+	}
+	branches := make(map[string]*release.FreezeCalendar, len(opts.BranchFreezeCalendars))
+	for branch, c := range opts.BranchFreezeCalendars {
+		if branches[branch], err = freezeCalendar(c); err != nil {
+			return nil, fmt.Errorf("freeze calendar of %s: %w", branch, err)
+		}
+	}
+
+	freeze := release.NewFreeze(calendar, branches)
+	freeze.SetOverride(overrideReason)
+	return freeze, nil
+}
+
+// freezeCalendar returns the freeze calendar of the config, with the events of its iCal file.
+func freezeCalendar(c *config.FreezeCalendar) (*release.FreezeCalendar, error) {
+	if c == nil {
+		return nil, nil
+	}
+	calendar := &release.FreezeCalendar{Location: opts.ScheduleLocation}
+	for _, w := range c.Windows {
+		calendar.Windows = append(calendar.Windows, release.FreezeWindow{Start: w.Start, End: w.End, Reason: w.Reason})
+	}
+	for _, w := range c.Weekly {
+		calendar.Weekly = append(calendar.Weekly, release.WeeklyFreeze{
+			StartDay: w.StartDay, Start: w.Start, EndDay: w.EndDay, End: w.End, Reason: w.Reason,
+		})
+	}
+
+	if c.ICalFile == "" {
+		return calendar, nil
+	}
+	path, err := homedir.Expand(c.ICalFile)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading freeze calendar: %w", err)
+	}
+	defer file.Close()
+	windows, err := release.ParseICal(file, opts.ScheduleLocation, time.Now().AddDate(icalYears, 0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing freeze calendar %s: %w", c.ICalFile, err)
+	}
+	calendar.Windows = append(calendar.Windows, windows...)
+	return calendar, nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+	"github.com/beatlabs/ergo/config"
+	"github.com/beatlabs/ergo/git"
+	"github.com/beatlabs/ergo/github"
+	"github.com/beatlabs/ergo/gitlab"
+	"github.com/beatlabs/ergo/release"
+	ergoTime "github.com/beatlabs/ergo/time"
+	gogithub "github.com/google/go-github/v41/github"
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/oauth2"
+)
+
+// newHost creates the host implementation selected by the config.
+func newHost(ctx context.Context) (ergo.Host, error) {
+	switch opts.Host {
+	case config.HostGitlab:
+		gitlabClient, err := gitlab.NewGitlabClient(opts.GitlabBaseURL, opts.AccToken)
+		if err != nil {
+			return nil, err
+		}
+		return gitlab.NewRepositoryClient(opts.Organization, opts.RepoName, gitlabClient), nil
+	case config.HostGit:
+		repo, err := git.Open(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		return git.NewRepositoryClient(opts.Path, opts.GenericRemote, opts.AccToken, repo), nil
+	case config.HostGithub, "":
+		githubClient, err := newGithubClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		repoClient := github.NewRepositoryClient(opts.Organization, opts.RepoName, githubClient)
+		repoClient.SetCompareWorkers(opts.CompareWorkers)
+		return repoClient, nil
+	default:
+		return nil, fmt.Errorf("unknown host %q", opts.Host)
+	}
+}
+
+// newGithubClient creates the client of GitHub, or of the GitHub Enterprise Server if a base URL
+// is configured, retrying the failing requests.
+func newGithubClient(ctx context.Context) (*gogithub.Client, error) {
+	transport := github.NewTransport(nil, ergoTime.Time{})
+	transport.SetRetries(opts.GithubMaxRetries, opts.GithubMinBackoff, opts.GithubMaxBackoff)
+	transport.SetMaxWait(opts.GithubMaxWait)
+	if verbose {
+		prt, err := newPrinter()
+		if err != nil {
+			return nil, err
+		}
+		transport.SetRateLimitObserver(printRateLimit(prt))
+	}
+
+	ts, err := githubTokenSource(transport)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.GithubBaseURL != "" {
+		return github.NewGithubEnterpriseClient(ctx, opts.GithubBaseURL, opts.GithubUploadURL, ts, transport)
+	}
+	return github.NewGithubClient(ctx, ts, transport), nil
+}
+
+// githubTokenSource returns the installation tokens of the GitHub App, if one is configured, or the
+// access token.
+func githubTokenSource(transport http.RoundTripper) (oauth2.TokenSource, error) {
+	app := opts.GithubApp
+	if app == nil {
+		return github.NewTokenSource(opts.AccToken), nil
+	}
+
+	keyPath, err := homedir.Expand(app.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading github app private key: %w", err)
+	}
+	return github.NewInstallationTokenSource(app.AppID, app.InstallationID, privateKey, opts.GithubBaseURL, transport, ergoTime.Time{})
+}
+
+// printRateLimit returns the rate limit observer printing the remaining quota of the requests.
+func printRateLimit(prt ergo.CLI) func(github.RateLimit) {
+	return func(rl github.RateLimit) {
+		resource := rl.Resource
+		if resource == "" {
+			resource = "core"
+		}
+		prt.PrintColorizedLine("RATE LIMIT: ",
+			fmt.Sprintf("%d/%d %s requests remaining, resets at %s", rl.Remaining, rl.Limit, resource, rl.Reset.Format("15:04:05")),
+			cli.InfoType)
+	}
+}
+
+// newVersion creates the version calculator, using calendar versioning if a format is configured.
+func newVersion(host ergo.Host) *release.Version {
+	if opts.CalVerFormat != "" {
+		return release.NewCalVersion(host, opts.BaseBranch, opts.CalVerFormat)
+	}
+	return release.NewVersion(host, opts.BaseBranch)
+}
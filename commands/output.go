@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+)
+
+var (
+	// outputFormat is the format of the output selected by the global --output flag.
+	outputFormat string
+	// verbose is set by the global --verbose flag to print details such as the API quota.
+	verbose bool
+)
+
+// newPrinter creates the CLI printing in the selected output format.
+func newPrinter() (ergo.CLI, error) {
+	if outputFormat == "" || outputFormat == cli.OutputTable {
+		return cli.NewCLI(), nil
+	}
+	return cli.NewStructuredCLI(outputFormat, os.Stdin, os.Stdout, os.Stderr)
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/beatlabs/ergo/release"
+	"github.com/spf13/cobra"
+)
+
+// defineRollbackCommand defines the rollback command.
+func defineRollbackCommand() *cobra.Command {
+	var (
+		releaseOffset   string
+		releaseInterval string
+		branchesString  string
+		tagName         string
+		skipConfirm     bool
+		overrideFreeze  string
+	)
+
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore target branches to the previous release",
+		Long:  "Force update target branches to the previously deployed release tag, or to the given tag",
+	}
+
+	rollbackCmd.Flags().StringVar(&releaseOffset, "releaseOffset", "1m", "Duration to wait before the first rollback ('5m', '1h25m', '30s')")
+	rollbackCmd.Flags().StringVar(&releaseInterval, "releaseInterval", "25m", "Duration to wait between rollbacks. ('5m', '1h25m', '30s')\n"+
+		"You can do a non-linear interval by supplying more values: ('15m,10m,5m,5m,5m')")
+	rollbackCmd.Flags().StringVar(&branchesString, "branches", "", "Comma separated list of branches")
+	rollbackCmd.Flags().StringVar(&tagName, "tag", "", "The release tag to roll back to. If empty, the release before the latest one will be used")
+	rollbackCmd.Flags().BoolVar(&skipConfirm, "skip-confirmation", false, "Roll back without asking for user confirmation.")
+	rollbackCmd.Flags().StringVar(&overrideFreeze, "override-freeze", "", "Roll back even if a branch is planned in a freeze window, recording the reason in the release body.")
+
+	rollbackCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return defineRollbackCommandRun(releaseInterval, releaseOffset, branchesString, tagName, overrideFreeze, skipConfirm)
+	}
+
+	return rollbackCmd
+}
+
+// defineRollbackCommandRun defines the rollback command run actions.
+func defineRollbackCommandRun(releaseInterval, releaseOffset, branchesString, tagName, overrideFreeze string, skipConfirm bool) error {
+	ctx := context.Background()
+
+	if branchesString != "" {
+		vipOpts.SetReleaseBranches(branchesString)
+	}
+
+	freeze, err := newFreeze(overrideFreeze)
+	if err != nil {
+		return err
+	}
+
+	host, err := newHost(ctx)
+	if err != nil {
+		return err
+	}
+
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+
+	deploy := release.NewDeploy(
+		printer,
+		host,
+		opts.BaseBranch,
+		opts.ReleaseBodyFind,
+		opts.ReleaseBodyReplace,
+		opts.ReleaseBranches,
+		opts.ReleaseBodyBranches,
+	)
+
+	deploy.SetScheduleLocation(opts.ScheduleLocation)
+	deploy.SetBoardLocations(opts.BoardLocations)
+	deploy.SetFreeze(freeze)
+
+	return deploy.Rollback(ctx, releaseInterval, releaseOffset, tagName, skipConfirm)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/beatlabs/ergo/cli"
 	"github.com/beatlabs/ergo/config"
 	"github.com/beatlabs/ergo/config/viper"
 	"github.com/spf13/cobra"
@@ -23,6 +24,8 @@ func Execute(version string) {
 	rootCommand.AddCommand(defineVersionCommand(version))
 	rootCommand.AddCommand(defineDraftCommand())
 	rootCommand.AddCommand(defineDeployCommand())
+	rootCommand.AddCommand(defineRollbackCommand())
+	rootCommand.AddCommand(defineConfigCommand())
 	if err := rootCommand.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -30,9 +33,12 @@ func Execute(version string) {
 }
 
 // initConfig initializes the config and sets the global opts variable which is shared by all commands.
-func initConfig() {
+// The config of the repository at the path is layered over the home config, and the profile over both.
+func initConfig(path, profile string) {
 	var err error
 	vipOpts = viper.NewOptions()
+	vipOpts.Path = path
+	vipOpts.Profile = profile
 
 	opts, err = vipOpts.InitConfig()
 	if err != nil {
@@ -50,18 +56,22 @@ func defineRootCommandProperties(rootCmd *cobra.Command) {
 		branchesString string
 		repoName       string
 		owner          string
+		profile        string
 	)
 
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(func() { initConfig(path, profile) })
 
 	rootCmd.PersistentFlags().StringVar(&path, "path", ".", "Location to store or retrieve from the repo")
 	rootCmd.PersistentFlags().StringVar(&branchesString, "branches", "", "Comma separated list of branches")
 	rootCmd.PersistentFlags().StringVar(&baseBranch, "base", "", "Base branch for the comparison.")
 	rootCmd.PersistentFlags().StringVar(&owner, "owner", "", "")
 	rootCmd.PersistentFlags().StringVar(&repoName, "repo", "", "")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile under profiles in the config whose settings are used")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", cli.OutputTable, "Output format: table, json, yaml or csv")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Print details such as the remaining API quota")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		return persistentPreRunECommand(cmd.Name(), baseBranch, branchesString, repoName, owner)
+		return persistentPreRunECommand(cmd, path, baseBranch, branchesString, repoName, owner)
 	}
 
 	rootCmd.Run = func(cmd *cobra.Command, args []string) {
@@ -70,22 +80,31 @@ func defineRootCommandProperties(rootCmd *cobra.Command) {
 }
 
 // persistentPreRunECommand defines the root command actions before the run command.
-func persistentPreRunECommand(cmdName, baseBranch, branchesString, repoName, owner string) error {
+func persistentPreRunECommand(cmd *cobra.Command, path, baseBranch, branchesString, repoName, owner string) error {
 	var err error
 	// commands not requiring a repo
 	noRepoCmds := make(map[string]bool)
 	noRepoCmds["help"] = true
 	noRepoCmds["version"] = true
 
-	if _, ok := noRepoCmds[cmdName]; ok {
+	if _, ok := noRepoCmds[cmd.Name()]; ok {
 		return nil
 	}
 
+	if _, err = newPrinter(); err != nil {
+		return err
+	}
+
+	vipOpts.Path = path
 	vipOpts.BaseBranch = baseBranch
 	vipOpts.SetBranchesString(branchesString)
 	vipOpts.RepoName = repoName
 	vipOpts.Organization = owner
 	vipOpts.RefreshConfig()
+	// the config commands create or check the config, which may not be valid yet
+	if cmd.HasParent() && cmd.Parent().Name() == "config" {
+		return nil
+	}
 	opts, err = vipOpts.GetConfig()
 	if err != nil {
 		return fmt.Errorf("error Initializing ergo options %v", err)
@@ -3,8 +3,7 @@ package commands
 import (
 	"context"
 	"strconv"
-
-	"github.com/beatlabs/ergo/github"
+	"strings"
 
 	"github.com/beatlabs/ergo"
 	"github.com/beatlabs/ergo/cli"
@@ -20,33 +19,47 @@ func defineStatusCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
-			githubClient := github.NewGithubClient(ctx, opts.AccToken)
-			host := github.NewRepositoryClient(opts.Organization, opts.RepoName, githubClient)
+			host, err := newHost(ctx)
+			if err != nil {
+				return err
+			}
 
-			diff, err := host.DiffCommits(ctx, opts.Branches, opts.BaseBranch)
+			prt, err := newPrinter()
 			if err != nil {
 				return err
 			}
-			printBranchCompare(diff, host.GetRepoName())
-			return nil
+
+			// The branches which could be compared are printed even if others failed.
+			diff, errDiff := host.DiffCommits(ctx, opts.Branches, opts.BaseBranch)
+			if len(diff) > 0 || errDiff == nil {
+				printBranchCompare(prt, diff, host.GetRepoName())
+			}
+			return errDiff
 		},
 	}
 }
 
 // printBranchCompare prints the status.
-func printBranchCompare(commitDiffBranches []*ergo.StatusReport, repoName string) {
-	prt := cli.NewCLI()
+func printBranchCompare(prt ergo.CLI, commitDiffBranches []*ergo.StatusReport, repoName string) {
 	prt.PrintColorizedLine("REPO: ", repoName, cli.WarningType)
 	if len(commitDiffBranches) > 0 {
 		prt.PrintColorizedLine("BASE: ", commitDiffBranches[0].BaseBranch, cli.WarningType)
 	}
 	headers := []string{"Branch", "Behind", "Ahead"}
 	var body [][]string
+	var truncated []string
 	for _, diff := range commitDiffBranches {
-		behind := strconv.Itoa(len(diff.Behind))
-		ahead := strconv.Itoa(len(diff.Ahead))
+		behind := strconv.Itoa(diff.BehindBy)
+		ahead := strconv.Itoa(diff.AheadBy)
 		row := []string{diff.Branch, behind, ahead}
 		body = append(body, row)
+		if diff.Truncated {
+			truncated = append(truncated, diff.Branch)
+		}
 	}
 	prt.PrintTable(headers, body)
+	if len(truncated) > 0 {
+		prt.PrintColorizedLine("TRUNCATED: ", "not all the commits of "+strings.Join(truncated, ", ")+" could be listed", cli.WarningType)
+	}
+	prt.PrintObject(commitDiffBranches)
 }
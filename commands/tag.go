@@ -2,9 +2,9 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
-	"github.com/beatlabs/ergo/github"
 	"github.com/beatlabs/ergo/release"
 
 	"github.com/beatlabs/ergo/cli"
@@ -17,6 +17,8 @@ func defineTagCommand() *cobra.Command {
 		suffix string
 		minor  bool
 		major  bool
+		auto   bool
+		dryRun bool
 	)
 
 	tagCmd := &cobra.Command{
@@ -28,21 +30,43 @@ func defineTagCommand() *cobra.Command {
 	tagCmd.Flags().StringVar(&suffix, "suffix", "", "The suffix of the tag.")
 	tagCmd.Flags().BoolVar(&minor, "minor", false, "The minor part of the tag.")
 	tagCmd.Flags().BoolVar(&major, "major", false, "The major part of the tag.")
+	tagCmd.Flags().BoolVar(&auto, "auto", false, "Infer the version increase from the conventional commits not deployed to the release branches.")
+	tagCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the tag without creating it.")
 
 	tagCmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
+		if auto && (major || minor) {
+			return errors.New("--auto can not be combined with --major or --minor")
+		}
+
 		var versionArg string
 		if len(args) > 0 {
 			versionArg = args[0]
 		}
 
-		var prt = cli.NewCLI()
+		prt, err := newPrinter()
+		if err != nil {
+			return err
+		}
 
-		githubClient := github.NewGithubClient(ctx, opts.AccToken)
-		host := github.NewRepositoryClient(opts.Organization, opts.RepoName, githubClient)
+		host, err := newHost(ctx)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			host = release.NewDryRunHost(host, prt)
+		}
+
+		versions := newVersion(host)
+		if auto {
+			major, minor, err = versions.AutoBump(ctx, opts.ReleaseBranches)
+			if err != nil {
+				return err
+			}
+		}
 
-		ver, err := release.NewVersion(host, opts.BaseBranch).NextVersion(ctx, versionArg, suffix, major, minor)
+		ver, err := versions.NextVersion(ctx, versionArg, suffix, major, minor)
 		if err != nil {
 			return err
 		}
@@ -70,13 +94,15 @@ func defineTagCommand() *cobra.Command {
 			}
 		}
 
-		confirmationMessage := fmt.Sprintf("Create tag %q on %v", ver.Name, ver.SHA)
-		confirm, err := prt.Confirmation(confirmationMessage, "Aborting...", "Creating tag...")
-		if err != nil {
-			return err
-		}
-		if !confirm {
-			return nil
+		if !dryRun {
+			confirmationMessage := fmt.Sprintf("Create tag %q on %v", ver.Name, ver.SHA)
+			confirm, errConfirm := prt.Confirmation(confirmationMessage, "Aborting...", "Creating tag...")
+			if errConfirm != nil {
+				return errConfirm
+			}
+			if !confirm {
+				return nil
+			}
 		}
 
 		newTag, err := tag.Create(ctx, ver)
@@ -84,6 +110,9 @@ func defineTagCommand() *cobra.Command {
 			return err
 		}
 
+		if dryRun {
+			return nil
+		}
 		prt.PrintColorizedLine("", fmt.Sprintf("Successfully created tag: %q", newTag.Name), cli.SuccessType)
 
 		return nil
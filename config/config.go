@@ -1,6 +1,19 @@
 package config
 
-import "gopkg.in/src-d/go-git.v4/plumbing/format/config"
+import (
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/format/config"
+)
+
+const (
+	// HostGithub selects the github host.
+	HostGithub = "github"
+	// HostGitlab selects the gitlab host.
+	HostGitlab = "gitlab"
+	// HostGit selects the local git repository found at the path as host.
+	HostGit = "git"
+)
 
 // Options include the base configuration for creating draft, releasing deployments, statusing etc.
 type Options struct {
@@ -9,15 +22,100 @@ type Options struct {
 	ReleaseBranches []string
 	AccToken        string
 
+	Host          string
+	GitlabBaseURL string
+
+	CompareWorkers int
+
+	GithubBaseURL    string
+	GithubUploadURL  string
+	GithubApp        *GithubApp
+	GithubMaxRetries int
+	GithubMinBackoff time.Duration
+	GithubMaxBackoff time.Duration
+	GithubMaxWait    time.Duration
+
 	ReleaseBodyBranches map[string]string
 	ReleaseBodyPrefix   string
 	ReleaseBodyFind     string
 	ReleaseBodyReplace  string
+	ReleaseBodyTemplate string
+	PullRequestLabels   map[string]string
+	CalVerFormat        string
+	DeployStateDir      string
+
+	ChecksPollInterval time.Duration
+	ChecksTimeout      time.Duration
+	HealthChecks       map[string]*HealthCheck
+	RolloutWaves       []RolloutWave
+
+	// ScheduleLocation is the time zone of the start time given with --at and of the freeze
+	// calendar, and BoardLocations are the time zones the schedule board is printed in.
+	ScheduleLocation *time.Location
+	BoardLocations   []*time.Location
+	// FreezeCalendar applies to the release branches without their own in BranchFreezeCalendars.
+	FreezeCalendar        *FreezeCalendar
+	BranchFreezeCalendars map[string]*FreezeCalendar
 
 	GenericRemote string
+	Path          string
 
 	Organization string
 	RepoName     string
+
+	// Profile is the name of the profile under profiles whose settings are layered over the config.
+	Profile string
+}
+
+// GithubApp is the GitHub App authenticating instead of a personal access token.
+type GithubApp struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKeyPath string
+}
+
+// HealthCheck is the probe of the environment of a release branch after it is deployed.
+type HealthCheck struct {
+	URL            string
+	ExpectedStatus int
+	JSONField      string
+	ExpectedValue  string
+	PollInterval   time.Duration
+	Timeout        time.Duration
+	Rollback       bool
+}
+
+// RolloutWave is a group of release branches deployed together, in the order of the waves.
+type RolloutWave struct {
+	Name     string        `mapstructure:"name"`
+	Branches []string      `mapstructure:"branches"`
+	Delay    time.Duration `mapstructure:"delay"`
+	Confirm  bool          `mapstructure:"confirm"`
+}
+
+// FreezeCalendar is the periods in which the release branches may not be deployed.
+type FreezeCalendar struct {
+	Windows []FreezeWindow
+	Weekly  []WeeklyFreeze
+	// ICalFile is an iCal file whose events are freeze windows too.
+	ICalFile string
+}
+
+// FreezeWindow is a period in which no release branch may be deployed.
+type FreezeWindow struct {
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
+
+// WeeklyFreeze is a freeze window recurring every week, Start and End being the durations since
+// the midnight of their day.
+type WeeklyFreeze struct {
+	StartDay time.Weekday
+	Start    time.Duration
+	EndDay   time.Weekday
+	End      time.Duration
+	Reason   string
 }
 
 // Config interface describes the config initialization.
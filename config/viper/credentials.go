@@ -0,0 +1,183 @@
+package viper
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/beatlabs/ergo/config"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// CredentialSource is a source of the access token. Token returns an empty token if the source
+// has none.
+type CredentialSource struct {
+	Name  string
+	Token func() (string, error)
+}
+
+// SetCredentialSources replaces the sources the access token is resolved from, in order, when it
+// is not set.
+func (o *Options) SetCredentialSources(sources ...CredentialSource) {
+	o.credentialSources = sources
+	o.customCredentialSources = true
+}
+
+// resolveAccessToken sets the access token from the first credential source which has one. It
+// returns the errors of the sources tried if none has.
+func (o *Options) resolveAccessToken() error {
+	sources := o.credentialSources
+	if !o.customCredentialSources {
+		sources = o.defaultCredentialSources()
+	}
+
+	var tried []string
+	for _, source := range sources {
+		token, err := source.Token()
+		if err != nil {
+			tried = append(tried, fmt.Sprintf("%s (%v)", source.Name, err))
+			continue
+		}
+		if token = strings.TrimSpace(token); token != "" {
+			o.AccToken = token
+			o.accessTokenSource = source.Name
+			return nil
+		}
+		tried = append(tried, source.Name)
+	}
+	return fmt.Errorf("invalid field: access token, none found in %s", strings.Join(tried, ", "))
+}
+
+// defaultCredentialSources returns the sources of the access token of the host: the
+// ERGO_<HOST>_TOKEN and <HOST>_TOKEN env vars, the access-token and token-command config keys,
+// the hosts.yml of the gh CLI for github and the git credential helpers.
+func (o *Options) defaultCredentialSources() []CredentialSource {
+	host := o.Host
+	if host == "" {
+		host = config.HostGithub
+	}
+	envPrefix := strings.ToUpper(host)
+	credentialHost := o.credentialHost()
+	accessTokenKey, tokenCommandKey := o.key(host+".access-token"), o.key(host+".token-command")
+
+	sources := []CredentialSource{
+		envCredentialSource("ERGO_" + envPrefix + "_TOKEN"),
+		envCredentialSource(envPrefix + "_TOKEN"),
+		{
+			Name:  accessTokenKey,
+			Token: func() (string, error) { return viper.GetString(accessTokenKey), nil },
+		},
+		{
+			Name:  tokenCommandKey,
+			Token: func() (string, error) { return tokenCommand(viper.GetString(tokenCommandKey)) },
+		},
+	}
+	if host == config.HostGithub {
+		sources = append(sources, CredentialSource{
+			Name:  "gh hosts.yml",
+			Token: func() (string, error) { return ghHostsToken(credentialHost) },
+		})
+	}
+	return append(sources, CredentialSource{
+		Name:  "git credential fill",
+		Token: func() (string, error) { return gitCredentialToken(credentialHost) },
+	})
+}
+
+// credentialHost returns the hostname the credentials of the host are stored for.
+func (o *Options) credentialHost() string {
+	baseURL, defaultHost := o.GithubBaseURL, "github.com"
+	if o.Host == config.HostGitlab {
+		baseURL, defaultHost = o.GitlabBaseURL, "gitlab.com"
+	}
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return defaultHost
+}
+
+// envCredentialSource returns the source of the token in the env var.
+func envCredentialSource(name string) CredentialSource {
+	return CredentialSource{
+		Name:  name,
+		Token: func() (string, error) { return os.Getenv(name), nil },
+	}
+}
+
+// tokenCommand runs the command with the shell and returns its output, e.g. "pass show github".
+func tokenCommand(command string) (string, error) {
+	if command == "" {
+		return "", nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// commands like pass print metadata after the first line
+	return strings.SplitN(string(out), "\n", 2)[0], nil
+}
+
+// ghHostsToken returns the token of the host stored by the gh CLI in its hosts.yml, found in
+// $GH_CONFIG_DIR, $XDG_CONFIG_HOME/gh or ~/.config/gh.
+func ghHostsToken(host string) (string, error) {
+	dir := os.Getenv("GH_CONFIG_DIR")
+	if dir == "" && os.Getenv("XDG_CONFIG_HOME") != "" {
+		dir = filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "gh")
+	}
+	if dir == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config", "gh")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("error parsing gh hosts.yml: %w", err)
+	}
+	return hosts[host].OAuthToken, nil
+}
+
+// gitCredentialToken asks the git credential helpers for the password of the host, without
+// prompting the user.
+func gitCredentialToken(host string) (string, error) {
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	out, err := cmd.Output()
+	if err != nil {
+		// git fails when no helper has the credentials and it is not allowed to prompt
+		return "", nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if password := strings.TrimPrefix(scanner.Text(), "password="); password != scanner.Text() {
+			return password, nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,158 @@
+package viper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolveAccessTokenShouldUseTheFirstSourceWithAToken(t *testing.T) {
+	vipOpts := NewOptions()
+	vipOpts.SetCredentialSources(
+		CredentialSource{Name: "empty", Token: func() (string, error) { return "", nil }},
+		CredentialSource{Name: "failing", Token: func() (string, error) { return "", errors.New("boom") }},
+		CredentialSource{Name: "command", Token: func() (string, error) { return "secret\n", nil }},
+		CredentialSource{Name: "unused", Token: func() (string, error) { return "other", nil }},
+	)
+
+	if err := vipOpts.resolveAccessToken(); err != nil {
+		t.Fatalf("resolveAccessToken should not return the error: %v", err)
+	}
+	if vipOpts.AccToken != "secret" {
+		t.Errorf("expected the token of the command, got %q", vipOpts.AccToken)
+	}
+}
+
+func TestResolveAccessTokenShouldReturnTheSourcesTried(t *testing.T) {
+	vipOpts := NewOptions()
+	vipOpts.SetCredentialSources(
+		CredentialSource{Name: "GITHUB_TOKEN", Token: func() (string, error) { return "", nil }},
+		CredentialSource{Name: "github.token-command", Token: func() (string, error) { return "", errors.New("exit status 1") }},
+	)
+
+	err := vipOpts.resolveAccessToken()
+	if err == nil {
+		t.Fatal("resolveAccessToken should return the error if no source has a token")
+	}
+	want := "invalid field: access token, none found in GITHUB_TOKEN, github.token-command (exit status 1)"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestDefaultCredentialSourcesShouldPreferTheEnv(t *testing.T) {
+	t.Setenv("ERGO_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	v := viper.GetViper()
+	v.Set("github.access-token", "from-config")
+	defer v.Set("github.access-token", "")
+
+	vipOpts := NewOptions()
+	vipOpts.Host = "github"
+	if err := vipOpts.resolveAccessToken(); err != nil {
+		t.Fatalf("resolveAccessToken should not return the error: %v", err)
+	}
+	if vipOpts.AccToken != "from-env" {
+		t.Errorf("expected the token of the env, got %q", vipOpts.AccToken)
+	}
+}
+
+func TestDefaultCredentialSourcesShouldUseTheTokenOfTheRepo(t *testing.T) {
+	t.Setenv("ERGO_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	v := viper.GetViper()
+	v.Set("github.access-token", "from-config")
+	v.Set("repos.beatlabs/ergo.github.access-token", "from-repo")
+	defer func() {
+		v.Set("github.access-token", "")
+		v.Set("repos.beatlabs/ergo.github.access-token", "")
+	}()
+
+	vipOpts := NewOptions()
+	vipOpts.Host = "github"
+	vipOpts.Organization = "beatlabs"
+	vipOpts.RepoName = "ergo"
+	if err := vipOpts.resolveAccessToken(); err != nil {
+		t.Fatalf("resolveAccessToken should not return the error: %v", err)
+	}
+	if vipOpts.AccToken != "from-repo" || vipOpts.accessTokenSource != "repos.beatlabs/ergo.github.access-token" {
+		t.Errorf("expected the token of the repo, got %q from %s", vipOpts.AccToken, vipOpts.accessTokenSource)
+	}
+}
+
+func TestTokenCommandShouldReturnTheFirstLine(t *testing.T) {
+	token, err := tokenCommand(`printf 'secret\nurl: github.com\n'`)
+	if err != nil {
+		t.Fatalf("tokenCommand should not return the error: %v", err)
+	}
+	if token != "secret" {
+		t.Errorf("expected the first line, got %q", token)
+	}
+}
+
+func TestTokenCommandShouldReturnTheErrorOfTheCommand(t *testing.T) {
+	_, err := tokenCommand("echo 'no such entry' >&2; exit 1")
+	if err == nil || !strings.Contains(err.Error(), "no such entry") {
+		t.Errorf("expected the error with the output of the command, got %v", err)
+	}
+}
+
+func TestGhHostsTokenShouldReadTheTokenOfTheHost(t *testing.T) {
+	dir := t.TempDir()
+	hosts := "github.com:\n    user: octocat\n    oauth_token: gho_token\n    git_protocol: https\n" +
+		"github.example.com:\n    oauth_token: gho_enterprise\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	token, err := ghHostsToken("github.example.com")
+	if err != nil {
+		t.Fatalf("ghHostsToken should not return the error: %v", err)
+	}
+	if token != "gho_enterprise" {
+		t.Errorf("expected the token of the enterprise host, got %q", token)
+	}
+}
+
+func TestGhHostsTokenShouldIgnoreMissingFile(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	token, err := ghHostsToken("github.com")
+	if err != nil || token != "" {
+		t.Errorf("expected no token and no error, got %q and %v", token, err)
+	}
+}
+
+func TestGitCredentialTokenShouldAskTheCredentialHelpers(t *testing.T) {
+	home := t.TempDir()
+	gitConfig := "[credential]\n\thelper = \"!f() { echo username=x-access-token; echo password=from-helper; }; f\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(gitConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	token, err := gitCredentialToken("github.com")
+	if err != nil {
+		t.Fatalf("gitCredentialToken should not return the error: %v", err)
+	}
+	if token != "from-helper" {
+		t.Errorf("expected the password of the helper, got %q", token)
+	}
+}
+
+func TestCredentialHostShouldUseTheEnterpriseHost(t *testing.T) {
+	vipOpts := NewOptions()
+	vipOpts.Host = "github"
+	vipOpts.GithubBaseURL = "https://github.example.com/api/v3/"
+
+	if got := vipOpts.credentialHost(); got != "github.example.com" {
+		t.Errorf("expected the enterprise host, got %q", got)
+	}
+}
@@ -0,0 +1,152 @@
+package viper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/config"
+	"gopkg.in/yaml.v2"
+)
+
+// InitWizard asks for the settings ergo needs and writes them to a config file.
+type InitWizard struct {
+	c           ergo.CLI
+	remoteOwner string
+	remoteRepo  string
+}
+
+// NewInitWizard creates the wizard, suggesting the owner and the name of the repository at the
+// path from its origin remote.
+func NewInitWizard(c ergo.CLI, path string) *InitWizard {
+	w := &InitWizard{c: c}
+	_, originURL := localRepository(path)
+	w.remoteOwner, w.remoteRepo, _ = parseRemoteURL(originURL)
+	return w
+}
+
+// Run asks for the settings and writes them to the file, confirming before overwriting it. The
+// access token is left out of the file if none is given, since it can be resolved from the env,
+// the gh CLI or the git credential helpers.
+func (w *InitWizard) Run(file string) error {
+	if _, err := os.Stat(file); err == nil {
+		confirm, err := w.c.Confirmation("Overwrite "+file, "No changes made", "")
+		if err != nil || !confirm {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	host, err := w.askHost()
+	if err != nil {
+		return err
+	}
+	baseBranch, err := w.ask("Base branch", "develop")
+	if err != nil {
+		return err
+	}
+	statusBranches, err := w.ask("Comma separated branches compared by status", baseBranch)
+	if err != nil {
+		return err
+	}
+	releaseBranches, err := w.ask("Comma separated branches updated by deploy", "")
+	if err != nil {
+		return err
+	}
+
+	generic := yaml.MapSlice{
+		{Key: "base-branch", Value: baseBranch},
+		{Key: "status-branches", Value: statusBranches},
+	}
+	if releaseBranches != "" {
+		generic = append(generic, yaml.MapItem{Key: "release-branches", Value: releaseBranches})
+	}
+	settings := yaml.MapSlice{{Key: "host", Value: host}, {Key: "generic", Value: generic}}
+
+	if host != config.HostGit {
+		hostSettings, err := w.askHostSettings(host)
+		if err != nil {
+			return err
+		}
+		settings = append(settings, yaml.MapItem{Key: host, Value: hostSettings})
+	}
+
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("error writing %s: %w", file, err)
+	}
+	w.c.PrintLine("Config written to", file)
+	return nil
+}
+
+// askHost asks for the host until a known one is given.
+func (w *InitWizard) askHost() (string, error) {
+	for {
+		host, err := w.ask("Host (github, gitlab or git)", config.HostGithub)
+		if err != nil {
+			return "", err
+		}
+		switch host {
+		case config.HostGithub, config.HostGitlab, config.HostGit:
+			return host, nil
+		}
+		w.c.PrintLine("Unknown host", host)
+	}
+}
+
+// askHostSettings asks for the settings of the github or gitlab section.
+func (w *InitWizard) askHostSettings(host string) (yaml.MapSlice, error) {
+	var settings yaml.MapSlice
+
+	if host == config.HostGitlab {
+		baseURL, err := w.ask("Base URL of the API", "https://gitlab.com/api/v4/")
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, yaml.MapItem{Key: "base-url", Value: baseURL})
+	}
+	owner, err := w.ask("Owner", w.remoteOwner)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := w.ask("Repository", w.remoteRepo)
+	if err != nil {
+		return nil, err
+	}
+	token, err := w.ask("Access token (empty to use the env, gh or git credentials)", "")
+	if err != nil {
+		return nil, err
+	}
+
+	settings = append(settings,
+		yaml.MapItem{Key: "default-owner", Value: owner},
+		yaml.MapItem{Key: "default-repo", Value: repo},
+	)
+	if token != "" {
+		settings = append(settings, yaml.MapItem{Key: "access-token", Value: token})
+	}
+	return settings, nil
+}
+
+// ask prints the question and returns the answer, or the default value if the answer is empty.
+func (w *InitWizard) ask(question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		question = fmt.Sprintf("%s [%s]", question, defaultValue)
+	}
+	w.c.PrintLine(question + ":")
+
+	answer, err := w.c.Input()
+	if err != nil {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
@@ -0,0 +1,82 @@
+package viper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/beatlabs/ergo/mock"
+	"github.com/spf13/viper"
+)
+
+// answers returns the input of the mocked CLI answering the questions in order.
+func answers(values ...string) func() (string, error) {
+	return func() (string, error) {
+		value := values[0]
+		values = values[1:]
+		return value, nil
+	}
+}
+
+func TestInitWizardShouldWriteAValidConfig(t *testing.T) {
+	dir := initLocalRepository(t, "git@github.com:beatlabs/ergo.git", "")
+	file := filepath.Join(t.TempDir(), ".ergo.yaml")
+	c := &mock.CLI{InputFn: answers("bitbucket", "", "main", "", "release-a,release-b", "", "", "ghp_token")}
+
+	if err := NewInitWizard(c, dir).Run(file); err != nil {
+		t.Fatalf("Run should not return the error: %v", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the file to be private, got %v", info.Mode())
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("the config should be valid yaml: %v", err)
+	}
+	want := map[string]string{
+		"host":                     "github",
+		"generic.base-branch":      "main",
+		"generic.status-branches":  "main",
+		"generic.release-branches": "release-a,release-b",
+		"github.default-owner":     "beatlabs",
+		"github.default-repo":      "ergo",
+		"github.access-token":      "ghp_token",
+	}
+	for key, value := range want {
+		if got := v.GetString(key); got != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, got)
+		}
+	}
+	for _, key := range v.AllKeys() {
+		if !knownKey(strings.Split(key, ".")) {
+			t.Errorf("unexpected unknown key %s", key)
+		}
+	}
+}
+
+func TestInitWizardShouldNotOverwriteWithoutConfirmation(t *testing.T) {
+	file := filepath.Join(t.TempDir(), ".ergo.yaml")
+	if err := os.WriteFile(file, []byte("host: gitlab\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := &mock.CLI{ConfirmationFn: func() (bool, error) { return false, nil }}
+
+	if err := NewInitWizard(c, t.TempDir()).Run(file); err != nil {
+		t.Fatalf("Run should not return the error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "host: gitlab\n" {
+		t.Errorf("expected the file to be kept, got %q", data)
+	}
+}
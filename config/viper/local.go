@@ -0,0 +1,139 @@
+package viper
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogit "gopkg.in/src-d/go-git.v4"
+)
+
+// localConfigNames are the names of the repository-local config file, in order of precedence.
+var localConfigNames = []string{".ergo.yaml", ".ergo.yml"}
+
+// localBlockedSettings are the settings the repository-local config can't set at any level, since
+// a repository could otherwise run commands or send the token of the user to a host of its choice.
+var localBlockedSettings = []string{"access-token", "token-command", "base-url", "upload-url"}
+
+// localPathSettings are the settings of file paths ergo reads or writes, which the
+// repository-local config can only set to paths inside the repository, since a repository could
+// otherwise publish a file of the user, such as the home config, in the body of a release.
+var localPathSettings = []string{"body-template", "state-dir", "ical"}
+
+// localPathKey reports whether the key of the repository-local config is a file path.
+func localPathKey(key string) bool {
+	parts := strings.Split(key, ".")
+	for _, setting := range localPathSettings {
+		if parts[len(parts)-1] == setting {
+			return true
+		}
+	}
+	return false
+}
+
+// localPath returns the path of the repository-local config resolved against the root directory of
+// the repository. The second value is false if the path is outside the repository, including
+// through a symbolic link.
+func localPath(dir, path string) (string, bool) {
+	if path == "" {
+		return path, true
+	}
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "~") {
+		return "", false
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	resolved := filepath.Join(root, path)
+	if !insideDir(root, resolved) {
+		return "", false
+	}
+
+	realRoot, errRoot := filepath.EvalSymlinks(root)
+	realPath, errPath := filepath.EvalSymlinks(resolved)
+	if errRoot == nil && errPath == nil && !insideDir(realRoot, realPath) {
+		return "", false
+	}
+	return resolved, true
+}
+
+// insideDir reports whether the path is the directory or inside it.
+func insideDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// localBlockedKey reports whether the repository-local config can't set the key.
+func localBlockedKey(key string) bool {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if part == "app" && i > 0 && parts[i-1] == "github" {
+			return true
+		}
+	}
+	for _, setting := range localBlockedSettings {
+		if parts[len(parts)-1] == setting {
+			return true
+		}
+	}
+	return false
+}
+
+// localRepository returns the root directory of the git repository found at the path, or the path
+// itself if it is not in a repository, and the URL of its origin remote, if any.
+func localRepository(path string) (dir, originURL string) {
+	if path == "" {
+		path = "."
+	}
+
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return path, ""
+	}
+	if worktree, err := repo.Worktree(); err == nil {
+		dir = worktree.Filesystem.Root()
+	} else {
+		dir = path
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return dir, ""
+	}
+	return dir, remote.Config().URLs[0]
+}
+
+// localConfigFile returns the repository-local config file in the directory, if any.
+func localConfigFile(dir string) string {
+	for _, name := range localConfigNames {
+		file := filepath.Join(dir, name)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
+
+// parseRemoteURL returns the owner and the name of the repository of a remote URL, such as
+// https://github.com/beatlabs/ergo.git or git@github.com:beatlabs/ergo.git. The owner of a gitlab
+// project in a subgroup includes the subgroups.
+func parseRemoteURL(rawURL string) (owner, repo string, ok bool) {
+	var path string
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" && u.Host != "" {
+		path = u.Path
+	} else if i := strings.Index(rawURL, ":"); i > 0 && !strings.Contains(rawURL[:i], "/") {
+		// scp-like syntax, e.g. git@github.com:beatlabs/ergo.git
+		path = rawURL[i+1:]
+	} else {
+		return "", "", false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	i := strings.LastIndex(path, "/")
+	if i <= 0 || i == len(path)-1 {
+		return "", "", false
+	}
+	return path[:i], path[i+1:], true
+}
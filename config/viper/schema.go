@@ -0,0 +1,135 @@
+package viper
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// hostKeys are the keys of the section of every host. The default owner and repo are left out,
+// since they can't be overridden per repository.
+var hostKeys = []string{
+	"access-token",
+	"token-command",
+	"base-url",
+	"upload-url",
+	"release-body-prefix",
+	"compare-workers",
+	"app.id",
+	"app.installation-id",
+	"app.private-key-path",
+	"retry.max-retries",
+	"retry.min-backoff",
+	"retry.max-backoff",
+	"retry.max-wait",
+}
+
+// settingKeys are the keys of the settings which can be overridden per repository, "*" matching
+// any single segment such as a branch name.
+var settingKeys = []string{
+	"generic.base-branch",
+	"generic.status-branches",
+	"generic.release-branches",
+	"generic.remote",
+	"release.calver-format",
+	"release.rollout.waves",
+	"release.schedule.time-zone",
+	"release.schedule.board-time-zones",
+	"release.freeze.windows",
+	"release.freeze.weekly",
+	"release.freeze.ical",
+	"release.freeze.branches.*.windows",
+	"release.freeze.branches.*.weekly",
+	"release.freeze.branches.*.ical",
+	"release.body-template",
+	"release.branch-map.*",
+	"release.pull-requests.labels.*",
+	"release.on-deploy.body-branch-suffix-find",
+	"release.on-deploy.body-branch-suffix-replace",
+	"release.on-deploy.state-dir",
+	"release.on-deploy.checks.timeout",
+	"release.on-deploy.checks.poll-interval",
+	"release.on-deploy.health-checks.*.url",
+	"release.on-deploy.health-checks.*.expected-status",
+	"release.on-deploy.health-checks.*.json-field",
+	"release.on-deploy.health-checks.*.expected-value",
+	"release.on-deploy.health-checks.*.poll-interval",
+	"release.on-deploy.health-checks.*.timeout",
+	"release.on-deploy.health-checks.*.rollback",
+}
+
+// legacyRepoKeys are the keys found right under repos.<repo> before any setting could be
+// overridden per repository.
+var legacyRepoKeys = []string{
+	"status-branches",
+	"release-branches",
+	"calver-format",
+	"base-url",
+	"upload-url",
+}
+
+// hosts are the names of the host sections.
+var hosts = []string{"github", "gitlab", "git"}
+
+// UnknownKeys returns the keys of the config which ergo does not read, such as typos, sorted.
+func UnknownKeys() []string {
+	var unknown []string
+	for _, key := range viper.AllKeys() {
+		if !knownKey(strings.Split(key, ".")) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// knownKey reports whether the segments of the key are a known top level key, or a key of a
+// profile or a repository.
+func knownKey(segments []string) bool {
+	if len(segments) > 2 && segments[0] == "profiles" {
+		segments = segments[2:]
+	}
+	if len(segments) > 2 && segments[0] == "repos" {
+		return matchKey(segments[2:], append(repoSettingKeys(), legacyRepoKeys...))
+	}
+	if len(segments) == 1 {
+		return segments[0] == "host"
+	}
+	if segments[1] == "default-owner" || segments[1] == "default-repo" {
+		return len(segments) == 2 && matchKey(segments[:1], hosts)
+	}
+	return matchKey(segments, repoSettingKeys())
+}
+
+// repoSettingKeys returns the keys of all the settings which can be overridden per repository.
+func repoSettingKeys() []string {
+	keys := append([]string(nil), settingKeys...)
+	for _, host := range hosts {
+		for _, key := range hostKeys {
+			keys = append(keys, host+"."+key)
+		}
+	}
+	return keys
+}
+
+// matchKey reports whether the segments match one of the patterns.
+func matchKey(segments []string, patterns []string) bool {
+	for _, pattern := range patterns {
+		patternSegments := strings.Split(pattern, ".")
+		if len(patternSegments) != len(segments) {
+			continue
+		}
+		match := true
+		for i, s := range patternSegments {
+			if s != "*" && s != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
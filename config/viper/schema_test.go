@@ -0,0 +1,36 @@
+package viper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKnownKeyShouldMatchTheSettings(t *testing.T) {
+	tests := []struct {
+		key   string
+		known bool
+	}{
+		{"host", true},
+		{"github.access-token", true},
+		{"github.default-owner", true},
+		{"gitlab.base-url", true},
+		{"github.app.private-key-path", true},
+		{"release.branch-map.release-gr", true},
+		{"release.on-deploy.health-checks.release-gr.url", true},
+		{"repos.ergo.calver-format", true},
+		{"repos.beatlabs/ergo.generic.base-branch", true},
+		{"profiles.staging.github.default-owner", true},
+		{"profiles.staging.repos.ergo.release.calver-format", true},
+		{"github.acess-token", false},
+		{"generic.base_branch", false},
+		{"release.on-deploy.health-checks.release-gr.uri", false},
+		{"repos.ergo.github.default-owner", false},
+		{"repos.ergo.host", false},
+		{"hosts", false},
+	}
+	for _, tt := range tests {
+		if got := knownKey(strings.Split(tt.key, ".")); got != tt.known {
+			t.Errorf("knownKey(%q) = %v, want %v", tt.key, got, tt.known)
+		}
+	}
+}
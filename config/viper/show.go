@@ -0,0 +1,85 @@
+package viper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// secretKeys are the suffixes of the keys whose values are masked.
+var secretKeys = []string{"access-token", "password", "secret"}
+
+// Setting is a setting of the effective config and the source of its value.
+type Setting struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// configLayer is a source of the config and the keys it sets.
+type configLayer struct {
+	source string
+	keys   map[string]bool
+}
+
+// addLayer records the keys set by the source, layered over the previous sources.
+func (o *Options) addLayer(source string, keys []string) {
+	layer := configLayer{source: source, keys: make(map[string]bool, len(keys))}
+	for _, key := range keys {
+		layer.keys[key] = true
+	}
+	o.layers = append(o.layers, layer)
+}
+
+// Settings returns the settings of the effective config sorted by key, with the secrets masked,
+// followed by the access token if it has been resolved.
+func (o *Options) Settings() []Setting {
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+
+	settings := make([]Setting, 0, len(keys)+1)
+	for _, key := range keys {
+		value := fmt.Sprint(viper.Get(key))
+		if isSecret(key) {
+			value = mask(value)
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: o.source(key)})
+	}
+	if o.AccToken != "" && o.accessTokenSource != "" {
+		settings = append(settings, Setting{Key: "access token", Value: mask(o.AccToken), Source: o.accessTokenSource})
+	}
+	return settings
+}
+
+// source returns the last source setting the key, or the flags or the defaults if none does.
+func (o *Options) source(key string) string {
+	for i := len(o.layers) - 1; i >= 0; i-- {
+		if o.layers[i].keys[key] {
+			return o.layers[i].source
+		}
+	}
+	return "flags or defaults"
+}
+
+// isSecret reports whether the value of the key must be masked.
+func isSecret(key string) bool {
+	for _, suffix := range secretKeys {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask hides the value but its last 4 characters, if it is long enough not to reveal it.
+func mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) < 12 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
@@ -0,0 +1,56 @@
+package viper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestSettingsShouldShowTheSourceOfEveryValue(t *testing.T) {
+	home := t.TempDir()
+	homeConfig := "show-test:\n  home-only: home\n  overridden: home\n  access-token: ghp_0123456789abcdef\n" +
+		"profiles:\n  show:\n    show-test:\n      profiled: profile\n"
+	if err := os.WriteFile(filepath.Join(home, ".ergo.yaml"), []byte(homeConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+
+	dir := initLocalRepository(t, "https://github.com/beatlabs/ergo.git", "show-test:\n  overridden: local\n")
+
+	vipOpts := NewOptions()
+	vipOpts.Path = dir
+	vipOpts.Profile = "show"
+	if _, err := vipOpts.InitConfig(); err != nil {
+		t.Fatalf("InitConfig should not return the error: %v", err)
+	}
+
+	settings := make(map[string]Setting)
+	for _, setting := range vipOpts.Settings() {
+		settings[setting.Key] = setting
+	}
+
+	want := map[string]Setting{
+		"show-test.home-only":    {Key: "show-test.home-only", Value: "home", Source: filepath.Join(home, ".ergo.yaml")},
+		"show-test.overridden":   {Key: "show-test.overridden", Value: "local", Source: filepath.Join(dir, ".ergo.yaml")},
+		"show-test.profiled":     {Key: "show-test.profiled", Value: "profile", Source: "profile show"},
+		"show-test.access-token": {Key: "show-test.access-token", Value: "****cdef", Source: filepath.Join(home, ".ergo.yaml")},
+	}
+	for key, setting := range want {
+		if settings[key] != setting {
+			t.Errorf("expected %+v, got %+v", setting, settings[key])
+		}
+	}
+}
+
+func TestMaskShouldHideShortSecrets(t *testing.T) {
+	if got := mask("abcd"); got != "****" {
+		t.Errorf("expected the short secret to be hidden, got %q", got)
+	}
+	if got := mask(""); got != "" {
+		t.Errorf("expected an empty value, got %q", got)
+	}
+}
@@ -3,9 +3,12 @@ package viper
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/beatlabs/ergo/config"
+	ergoTime "github.com/beatlabs/ergo/time"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
@@ -16,6 +19,22 @@ type Options struct {
 
 	branchesString        string
 	releaseBranchesString string
+
+	credentialSources       []CredentialSource
+	customCredentialSources bool
+
+	// remoteOwner and remoteRepo are inferred from the origin remote of the local repository.
+	remoteOwner string
+	remoteRepo  string
+	homeDir     string
+
+	// layers are the sources of the config, in order of precedence, and accessTokenSource is the
+	// credential source the access token was resolved from.
+	layers            []configLayer
+	accessTokenSource string
+
+	rolloutWavesErr error
+	scheduleErr     error
 }
 
 // NewOptions factory.
@@ -23,22 +42,32 @@ func NewOptions() *Options {
 	return &Options{}
 }
 
-// InitConfig initializes configuration.
+// InitConfig initializes configuration from the home config file and the config file of the
+// repository found at the path, which takes precedence. The settings of the selected profile are
+// layered over both.
 func (o *Options) InitConfig() (*config.Options, error) {
-	err := initConfigFromFile()
+	o.layers = nil
+	dir, originURL := localRepository(o.Path)
+	err := o.initConfigFromFile(localConfigFile(dir))
+	if err != nil {
+		return nil, err
+	}
+	if err = o.applyProfile(o.Profile); err != nil {
+		return nil, err
+	}
+	o.remoteOwner, o.remoteRepo, _ = parseRemoteURL(originURL)
+	o.homeDir, err = homedir.Dir()
 	if err != nil {
 		return nil, err
 	}
 
-	o.AccToken = viper.GetString("github.access-token")
-
-	o.ReleaseBodyBranches = viper.GetStringMapString("release.branch-map")
-	o.ReleaseBodyPrefix = viper.GetString("github.release-body-prefix")
-	o.ReleaseBodyFind = viper.GetString("release.on-deploy.body-branch-suffix-find")
-	o.ReleaseBodyReplace = viper.GetString("release.on-deploy.body-branch-suffix-replace")
+	o.Host = viper.GetString("host")
+	if o.Host == "" {
+		o.Host = config.HostGithub
+	}
 
-	o.Organization = viper.GetString("github.default-owner")
-	o.RepoName = viper.GetString("github.default-repo")
+	o.setGenericConfigs()
+	o.setRepoConfigs()
 
 	return &o.Options, nil
 }
@@ -46,12 +75,21 @@ func (o *Options) InitConfig() (*config.Options, error) {
 // RefreshConfig refreshes configuration.
 func (o *Options) RefreshConfig() {
 	o.setGenericConfigs()
+	o.setRepoConfigs()
 	o.setStatusBranchConfig()
 	o.setReleaseBranchesConfig()
+	o.setVersionConfig()
+	o.setGithubURLConfig()
 }
 
 // GetConfig gets configuration.
 func (o *Options) GetConfig() (*config.Options, error) {
+	if o.AccToken == "" && o.needsAccessToken() {
+		if err := o.resolveAccessToken(); err != nil {
+			return nil, err
+		}
+	}
+
 	field, valid := o.validateOptions()
 	if !valid {
 		return nil, errors.New("invalid field: " + field)
@@ -77,15 +115,125 @@ func (o *Options) SetReleaseBranches(releaseBranchesString string) {
 
 // setGenericConfigs sets the generic configs.
 func (o *Options) setGenericConfigs() {
-	if o.BaseBranch == "" {
-		o.BaseBranch = viper.GetString("generic.base-branch")
+	if o.Organization == "" {
+		o.Organization = viper.GetString(o.hostKey("default-owner"))
 	}
 	if o.Organization == "" {
-		o.Organization = viper.GetString("github.default-owner")
+		o.Organization = o.remoteOwner
+	}
+	if o.RepoName == "" {
+		o.RepoName = viper.GetString(o.hostKey("default-repo"))
+	}
+	if o.RepoName == "" {
+		o.RepoName = o.remoteRepo
+	}
+	if o.BaseBranch == "" {
+		o.BaseBranch = viper.GetString(o.key("generic.base-branch"))
+	}
+}
+
+// setRepoConfigs sets the settings which can be overridden per repository.
+func (o *Options) setRepoConfigs() {
+	if o.Host == config.HostGit {
+		o.AccToken = viper.GetString(o.key(o.Host + ".access-token"))
+	}
+	o.GitlabBaseURL = viper.GetString(o.key("gitlab.base-url"))
+	o.CompareWorkers = viper.GetInt(o.key("github.compare-workers"))
+	o.GithubApp = githubApp(o.key("github.app"))
+	o.GithubMaxRetries = 3
+	if key := o.key("github.retry.max-retries"); viper.IsSet(key) {
+		o.GithubMaxRetries = viper.GetInt(key)
+	}
+	o.GithubMinBackoff = viper.GetDuration(o.key("github.retry.min-backoff"))
+	o.GithubMaxBackoff = viper.GetDuration(o.key("github.retry.max-backoff"))
+	o.GithubMaxWait = viper.GetDuration(o.key("github.retry.max-wait"))
+	o.GenericRemote = viper.GetString(o.key("generic.remote"))
+
+	o.ReleaseBodyBranches = viper.GetStringMapString(o.key("release.branch-map"))
+	o.ReleaseBodyPrefix = viper.GetString(o.key("github.release-body-prefix"))
+	o.ReleaseBodyFind = viper.GetString(o.key("release.on-deploy.body-branch-suffix-find"))
+	o.ReleaseBodyReplace = viper.GetString(o.key("release.on-deploy.body-branch-suffix-replace"))
+	o.ReleaseBodyTemplate = viper.GetString(o.key("release.body-template"))
+	o.PullRequestLabels = viper.GetStringMapString(o.key("release.pull-requests.labels"))
+	o.ChecksTimeout = viper.GetDuration(o.key("release.on-deploy.checks.timeout"))
+	o.ChecksPollInterval = viper.GetDuration(o.key("release.on-deploy.checks.poll-interval"))
+	if o.ChecksPollInterval <= 0 {
+		o.ChecksPollInterval = 30 * time.Second
+	}
+	o.HealthChecks = healthChecks(o.key("release.on-deploy.health-checks"))
+	o.RolloutWaves, o.rolloutWavesErr = rolloutWaves(o.key("release.rollout.waves"))
+	o.scheduleErr = o.setScheduleConfigs()
+	o.DeployStateDir = viper.GetString(o.key("release.on-deploy.state-dir"))
+	if o.DeployStateDir == "" {
+		o.DeployStateDir = o.homeDir
 	}
+}
+
+// key returns the config key of the setting, or the key overriding it under repos.<owner>/<repo>
+// or repos.<repo> if the repository has one.
+func (o *Options) key(name string) string {
 	if o.RepoName == "" {
-		o.RepoName = viper.GetString("github.default-repo")
+		return name
+	}
+
+	scopes := []string{"repos." + o.RepoName}
+	if o.Organization != "" {
+		scopes = append([]string{fmt.Sprintf("repos.%s/%s", o.Organization, o.RepoName)}, scopes...)
 	}
+	// the settings of the profile are layered over the repository overrides
+	for _, scope := range append(scopes, "") {
+		key := strings.TrimPrefix(scope+"."+name, ".")
+		if o.profileSets(key) {
+			return key
+		}
+	}
+	for _, scope := range scopes {
+		if key := scope + "." + name; viper.IsSet(key) {
+			return key
+		}
+	}
+	return name
+}
+
+// profileSets reports whether the selected profile sets the key or a setting under it.
+func (o *Options) profileSets(key string) bool {
+	for _, layer := range o.layers {
+		if !strings.HasPrefix(layer.source, "profile ") {
+			continue
+		}
+		for setting := range layer.keys {
+			if setting == key || strings.HasPrefix(setting, key+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyProfile layers the settings under profiles.<name> over the config.
+func (o *Options) applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	key := "profiles." + name
+	if !viper.IsSet(key) {
+		return fmt.Errorf("profile %s not found", name)
+	}
+
+	profile := viper.New()
+	if err := profile.MergeConfigMap(viper.GetStringMap(key)); err != nil {
+		return err
+	}
+	o.addLayer("profile "+name, profile.AllKeys())
+	return viper.MergeConfigMap(profile.AllSettings())
+}
+
+// hostKey returns the config key under the section of the configured host.
+func (o *Options) hostKey(key string) string {
+	if o.Host == "" {
+		return config.HostGithub + "." + key
+	}
+	return o.Host + "." + key
 }
 
 // setStatusBranchConfig sets the status branch config.
@@ -94,7 +242,7 @@ func (o *Options) setStatusBranchConfig() {
 		o.branchesString = viper.GetString(fmt.Sprintf("repos.%s.status-branches", o.RepoName))
 	}
 	if o.branchesString == "" {
-		o.branchesString = viper.GetString("generic.status-branches")
+		o.branchesString = viper.GetString(o.key("generic.status-branches"))
 	}
 	if o.branchesString != "" {
 		o.Branches = strings.Split(o.branchesString, ",")
@@ -107,15 +255,231 @@ func (o *Options) setReleaseBranchesConfig() {
 		o.releaseBranchesString = viper.GetString(fmt.Sprintf("repos.%s.release-branches", o.RepoName))
 	}
 	if o.releaseBranchesString == "" {
-		o.releaseBranchesString = viper.GetString("generic.release-branches")
+		o.releaseBranchesString = viper.GetString(o.key("generic.release-branches"))
 	}
 	if o.releaseBranchesString != "" {
 		o.ReleaseBranches = strings.Split(o.releaseBranchesString, ",")
 	}
 }
 
-// initConfigFromFile initialize the config from yaml file.
-func initConfigFromFile() error {
+// setVersionConfig sets the calendar version format, a repo specific format takes precedence.
+func (o *Options) setVersionConfig() {
+	if o.RepoName != "" {
+		o.CalVerFormat = viper.GetString(fmt.Sprintf("repos.%s.calver-format", o.RepoName))
+	}
+	if o.CalVerFormat == "" {
+		o.CalVerFormat = viper.GetString(o.key("release.calver-format"))
+	}
+}
+
+// setGithubURLConfig sets the GitHub Enterprise endpoints, the repo specific ones take precedence.
+func (o *Options) setGithubURLConfig() {
+	if o.RepoName != "" {
+		o.GithubBaseURL = viper.GetString(fmt.Sprintf("repos.%s.base-url", o.RepoName))
+		o.GithubUploadURL = viper.GetString(fmt.Sprintf("repos.%s.upload-url", o.RepoName))
+	}
+	if o.GithubBaseURL == "" {
+		o.GithubBaseURL = viper.GetString(o.key("github.base-url"))
+		o.GithubUploadURL = viper.GetString(o.key("github.upload-url"))
+	}
+}
+
+// githubApp reads the GitHub App credentials under the prefix, github.app by default, if an app
+// ID is configured.
+func githubApp(prefix string) *config.GithubApp {
+	appID := viper.GetInt64(prefix + ".id")
+	if appID == 0 {
+		return nil
+	}
+	return &config.GithubApp{
+		AppID:          appID,
+		InstallationID: viper.GetInt64(prefix + ".installation-id"),
+		PrivateKeyPath: viper.GetString(prefix + ".private-key-path"),
+	}
+}
+
+// healthChecks reads the health checks of the branches found under the prefix,
+// release.on-deploy.health-checks by default.
+func healthChecks(prefix string) map[string]*config.HealthCheck {
+	checks := make(map[string]*config.HealthCheck)
+	for branch := range viper.GetStringMap(prefix) {
+		key := func(name string) string { return fmt.Sprintf("%s.%s.%s", prefix, branch, name) }
+
+		check := &config.HealthCheck{
+			URL:            viper.GetString(key("url")),
+			ExpectedStatus: viper.GetInt(key("expected-status")),
+			JSONField:      viper.GetString(key("json-field")),
+			ExpectedValue:  viper.GetString(key("expected-value")),
+			PollInterval:   viper.GetDuration(key("poll-interval")),
+			Timeout:        viper.GetDuration(key("timeout")),
+			Rollback:       viper.GetBool(key("rollback")),
+		}
+		if check.PollInterval <= 0 {
+			check.PollInterval = 10 * time.Second
+		}
+		if check.Timeout <= 0 {
+			check.Timeout = 5 * time.Minute
+		}
+		checks[branch] = check
+	}
+	return checks
+}
+
+// rolloutWaves reads the waves of the rollout plan under the key, release.rollout.waves by default.
+func rolloutWaves(key string) ([]config.RolloutWave, error) {
+	var waves []config.RolloutWave
+	if err := viper.UnmarshalKey(key, &waves); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(waves))
+	branchWaves := make(map[string]string)
+	for i, wave := range waves {
+		if wave.Name == "" {
+			return nil, fmt.Errorf("wave %d has no name", i+1)
+		}
+		if names[wave.Name] {
+			return nil, fmt.Errorf("wave %s is defined twice", wave.Name)
+		}
+		if len(wave.Branches) == 0 {
+			return nil, fmt.Errorf("wave %s has no branches", wave.Name)
+		}
+		if wave.Delay < 0 {
+			return nil, fmt.Errorf("wave %s has a negative delay", wave.Name)
+		}
+		for _, branch := range wave.Branches {
+			if other, ok := branchWaves[branch]; ok {
+				return nil, fmt.Errorf("branch %s is in both waves %s and %s", branch, other, wave.Name)
+			}
+			branchWaves[branch] = wave.Name
+		}
+		names[wave.Name] = true
+	}
+	return waves, nil
+}
+
+// setScheduleConfigs sets the time zones of the schedule and the freeze calendars, which are
+// read in the time zone of the schedule, the local one by default.
+func (o *Options) setScheduleConfigs() error {
+	o.ScheduleLocation = time.Local
+	o.BoardLocations = nil
+	o.FreezeCalendar = nil
+	o.BranchFreezeCalendars = nil
+
+	if name := viper.GetString(o.key("release.schedule.time-zone")); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("time zone: %w", err)
+		}
+		o.ScheduleLocation = loc
+	}
+	for _, name := range viper.GetStringSlice(o.key("release.schedule.board-time-zones")) {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return fmt.Errorf("board time zone: %w", err)
+		}
+		o.BoardLocations = append(o.BoardLocations, loc)
+	}
+
+	calendar, err := freezeCalendar(o.key("release.freeze.windows"), o.key("release.freeze.weekly"),
+		o.key("release.freeze.ical"), o.ScheduleLocation)
+	if err != nil {
+		return fmt.Errorf("freeze, %w", err)
+	}
+	o.FreezeCalendar = calendar
+
+	o.BranchFreezeCalendars = make(map[string]*config.FreezeCalendar)
+	branchesKey := o.key("release.freeze.branches")
+	for branch := range viper.GetStringMap(branchesKey) {
+		prefix := branchesKey + "." + branch
+		calendar, err = freezeCalendar(prefix+".windows", prefix+".weekly", prefix+".ical", o.ScheduleLocation)
+		if err != nil {
+			return fmt.Errorf("freeze of %s, %w", branch, err)
+		}
+		o.BranchFreezeCalendars[branch] = calendar
+	}
+	return nil
+}
+
+// freezeCalendar reads the freeze windows, the weekly freeze windows and the iCal file of a
+// freeze calendar from the keys, the times being in the location.
+func freezeCalendar(windowsKey, weeklyKey, icalKey string, loc *time.Location) (*config.FreezeCalendar, error) {
+	calendar := &config.FreezeCalendar{ICalFile: viper.GetString(icalKey)}
+
+	var windows []struct {
+		Start  string `mapstructure:"start"`
+		End    string `mapstructure:"end"`
+		Reason string `mapstructure:"reason"`
+	}
+	if err := viper.UnmarshalKey(windowsKey, &windows); err != nil {
+		return nil, fmt.Errorf("windows: %w", err)
+	}
+	for i, w := range windows {
+		start, err := ergoTime.ParseWallClock(w.Start, loc)
+		if err != nil {
+			return nil, fmt.Errorf("window %d start: %w", i+1, err)
+		}
+		end, err := ergoTime.ParseWallClock(w.End, loc)
+		if err != nil {
+			return nil, fmt.Errorf("window %d end: %w", i+1, err)
+		}
+		if !end.After(start) {
+			return nil, fmt.Errorf("window %d ends before it starts", i+1)
+		}
+		calendar.Windows = append(calendar.Windows, config.FreezeWindow{Start: start, End: end, Reason: w.Reason})
+	}
+
+	var weekly []struct {
+		Start  string `mapstructure:"start"`
+		End    string `mapstructure:"end"`
+		Reason string `mapstructure:"reason"`
+	}
+	if err := viper.UnmarshalKey(weeklyKey, &weekly); err != nil {
+		return nil, fmt.Errorf("weekly windows: %w", err)
+	}
+	for i, w := range weekly {
+		startDay, start, err := weekTime(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("weekly window %d start: %w", i+1, err)
+		}
+		endDay, end, err := weekTime(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("weekly window %d end: %w", i+1, err)
+		}
+		calendar.Weekly = append(calendar.Weekly, config.WeeklyFreeze{
+			StartDay: startDay, Start: start, EndDay: endDay, End: end, Reason: w.Reason,
+		})
+	}
+	return calendar, nil
+}
+
+// weekTime parses a time of the week such as "Fri 14:00" or "friday 14:00", returning the day
+// and the duration since its midnight.
+func weekTime(value string) (time.Weekday, time.Duration, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 || len(fields[0]) < 3 {
+		return 0, 0, fmt.Errorf("invalid time of the week %q, expected e.g. Fri 14:00", value)
+	}
+	day := -1
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToLower(d.String()), strings.ToLower(fields[0])) {
+			day = int(d)
+		}
+	}
+	if day < 0 {
+		return 0, 0, fmt.Errorf("invalid day %q", fields[0])
+	}
+	clock, err := time.Parse("15:04", fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected e.g. 14:00", fields[1])
+	}
+	return time.Weekday(day), time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// initConfigFromFile initialize the config from the yaml file in the home directory, merging the
+// local file over it, if any. Both files are optional, since every setting has a default or can
+// be given with the flags, the env or the origin remote.
+func (o *Options) initConfigFromFile(localFile string) error {
 	home, err := homedir.Dir()
 	if err != nil {
 		return err
@@ -124,26 +488,92 @@ func initConfigFromFile() error {
 	viper.AddConfigPath(home)
 	viper.SetConfigName(".ergo")
 
-	return viper.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if err = viper.ReadInConfig(); err != nil && !errors.As(err, &notFound) {
+		return err
+	}
+	if err == nil {
+		o.addLayer(viper.ConfigFileUsed(), viper.AllKeys())
+	}
+	// the home directory is not a repository, so its config is not read as a local one
+	if localFile == "" || localFile == viper.ConfigFileUsed() {
+		return nil
+	}
+
+	local := viper.New()
+	local.SetConfigFile(localFile)
+	if err := local.ReadInConfig(); err != nil {
+		return fmt.Errorf("error reading %s: %w", localFile, err)
+	}
+	for _, key := range local.AllKeys() {
+		if localBlockedKey(key) {
+			return fmt.Errorf("%s can't set %s, since credentials and API endpoints are only read from the home config", localFile, key)
+		}
+		if localPathKey(key) {
+			path, ok := localPath(filepath.Dir(localFile), local.GetString(key))
+			if !ok {
+				return fmt.Errorf("%s can't set %s to %s, since paths outside the repository are only read from the home config",
+					localFile, key, local.GetString(key))
+			}
+			local.Set(key, path)
+		}
+	}
+	o.addLayer(localFile, local.AllKeys())
+	return viper.MergeConfigMap(local.AllSettings())
+}
+
+// needsAccessToken reports whether the host authenticates with an access token.
+func (o *Options) needsAccessToken() bool {
+	switch o.Host {
+	case "", config.HostGithub:
+		return o.GithubApp == nil
+	case config.HostGitlab:
+		return true
+	}
+	return false
 }
 
 // validateOptions validate the mandatory options.
 func (o *Options) validateOptions() (string, bool) {
-	if o.AccToken == "" {
-		return "access token", false
-	}
+	switch o.Host {
+	case "", config.HostGithub, config.HostGitlab:
+		if o.Host != config.HostGitlab && o.GithubApp != nil {
+			if o.GithubApp.InstallationID == 0 {
+				return "github app installation id", false
+			}
+			if o.GithubApp.PrivateKeyPath == "" {
+				return "github app private key path", false
+			}
+		} else if o.AccToken == "" {
+			return "access token", false
+		}
 
-	if o.Organization == "" {
-		return "organization", false
-	}
+		if o.Organization == "" {
+			return "organization", false
+		}
 
-	if o.RepoName == "" {
-		return "repository", false
+		if o.RepoName == "" {
+			return "repository", false
+		}
+	case config.HostGit:
+		if o.Path == "" {
+			return "path", false
+		}
+	default:
+		return "host", false
 	}
 
 	if o.BaseBranch == "" {
 		return "base branch", false
 	}
 
+	if o.rolloutWavesErr != nil {
+		return "rollout waves, " + o.rolloutWavesErr.Error(), false
+	}
+
+	if o.scheduleErr != nil {
+		return "schedule, " + o.scheduleErr.Error(), false
+	}
+
 	return "", true
 }
@@ -2,8 +2,12 @@ package viper
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
@@ -39,6 +43,7 @@ func TestGetConfigShouldReturnError(t *testing.T) {
 	v.Set("github.default-repo", "my-repo")
 
 	vipOpts := NewOptions()
+	vipOpts.SetCredentialSources()
 	vipOpts.RefreshConfig()
 	_, err := vipOpts.GetConfig()
 
@@ -152,3 +157,331 @@ func TestGetConfigShouldNotOverwriteNonEmptyValuesAfterRefresh(t *testing.T) {
 		t.Error("expected refresh config should not overwrite non empty.")
 	}
 }
+
+func TestGetConfigShouldPreferTheRepoCalVerFormat(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("github.access-token", "abcd")
+	v.Set("github.default-owner", "acme")
+	v.Set("github.default-repo", "my-repo")
+	v.Set("release.calver-format", "YYYY.MM.DD")
+	v.Set("repos.my-repo.calver-format", "YY.WW")
+
+	vipOpts := NewOptions()
+	vipOpts.AccToken = "abcd"
+	vipOpts.RefreshConfig()
+	opts, _ := vipOpts.GetConfig()
+
+	if opts.CalVerFormat != "YY.WW" {
+		t.Errorf("expected repo calver format, got %q", opts.CalVerFormat)
+	}
+}
+
+func TestGetConfigShouldAcceptGithubAppWithoutAccessToken(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("generic.base-branch", "master")
+	v.Set("github.default-owner", "acme")
+	v.Set("github.default-repo", "my-repo")
+	v.Set("github.app.id", 42)
+	v.Set("github.app.installation-id", 99)
+	v.Set("github.app.private-key-path", "~/.ergo-app.pem")
+	defer v.Set("github.app.id", 0)
+
+	vipOpts := NewOptions()
+	vipOpts.RefreshConfig()
+	opts, err := vipOpts.GetConfig()
+	if err != nil {
+		t.Fatalf("expected get config not to return error: %v", err)
+	}
+	if opts.GithubApp.AppID != 42 || opts.GithubApp.InstallationID != 99 || opts.GithubApp.PrivateKeyPath != "~/.ergo-app.pem" {
+		t.Errorf("unexpected github app %+v", opts.GithubApp)
+	}
+}
+
+func TestGetConfigShouldReturnErrorForIncompleteGithubApp(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("generic.base-branch", "master")
+	v.Set("github.default-owner", "acme")
+	v.Set("github.default-repo", "my-repo")
+	v.Set("github.app.id", 42)
+	v.Set("github.app.installation-id", 0)
+	v.Set("github.app.private-key-path", "~/.ergo-app.pem")
+	defer v.Set("github.app.id", 0)
+
+	vipOpts := NewOptions()
+	vipOpts.RefreshConfig()
+	_, err := vipOpts.GetConfig()
+	if err == nil || err.Error() != "invalid field: github app installation id" {
+		t.Errorf("expected the missing installation id error, got %v", err)
+	}
+}
+
+func TestGetConfigShouldPreferTheRepoGithubURLs(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("github.base-url", "https://github.example.com")
+	v.Set("repos.enterprise-repo.base-url", "https://github.acme.com")
+	v.Set("repos.enterprise-repo.upload-url", "https://uploads.github.acme.com")
+	defer v.Set("github.base-url", "")
+
+	vipOpts := NewOptions()
+	vipOpts.AccToken = "abcd"
+	vipOpts.RepoName = "enterprise-repo"
+	vipOpts.RefreshConfig()
+	opts, _ := vipOpts.GetConfig()
+	if opts.GithubBaseURL != "https://github.acme.com" || opts.GithubUploadURL != "https://uploads.github.acme.com" {
+		t.Errorf("expected the repo URLs, got %q and %q", opts.GithubBaseURL, opts.GithubUploadURL)
+	}
+
+	vipOpts = NewOptions()
+	vipOpts.AccToken = "abcd"
+	vipOpts.RepoName = "other-repo"
+	vipOpts.RefreshConfig()
+	opts, _ = vipOpts.GetConfig()
+	if opts.GithubBaseURL != "https://github.example.com" || opts.GithubUploadURL != "" {
+		t.Errorf("expected the github URLs, got %q and %q", opts.GithubBaseURL, opts.GithubUploadURL)
+	}
+}
+
+func TestHealthChecksShouldApplyDefaults(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("release.on-deploy.health-checks", map[string]interface{}{
+		"release-gr": map[string]interface{}{
+			"url":        "https://gr.example.com/health",
+			"json-field": "build.version",
+			"timeout":    "1m",
+			"rollback":   true,
+		},
+	})
+	defer v.Set("release.on-deploy.health-checks", nil)
+
+	checks := healthChecks("release.on-deploy.health-checks")
+
+	check, ok := checks["release-gr"]
+	if !ok {
+		t.Fatalf("expected the health check of release-gr, got %v", checks)
+	}
+	if check.URL != "https://gr.example.com/health" || check.JSONField != "build.version" || !check.Rollback {
+		t.Errorf("unexpected health check %+v", check)
+	}
+	if check.Timeout != time.Minute || check.PollInterval != 10*time.Second {
+		t.Errorf("got timeout %v and poll interval %v", check.Timeout, check.PollInterval)
+	}
+}
+
+func TestGetConfigShouldPreferTheSettingsOfTheRepo(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("github.access-token", "abcd")
+	v.Set("release.branch-map", map[string]string{"release-gr": ":greece:"})
+	v.Set("github.release-body-prefix", "Changelog:")
+	v.Set("repos.acme/scoped-repo.release.branch-map", map[string]string{"release-it": ":italy:"})
+	v.Set("repos.acme/scoped-repo.generic.base-branch", "main")
+	v.Set("repos.scoped-repo.github.release-body-prefix", "### Added")
+
+	vipOpts := NewOptions()
+	vipOpts.AccToken = "abcd"
+	vipOpts.Organization = "acme"
+	vipOpts.RepoName = "scoped-repo"
+	vipOpts.RefreshConfig()
+	opts, err := vipOpts.GetConfig()
+	if err != nil {
+		t.Fatalf("expected get config not to return error: %v", err)
+	}
+
+	if opts.ReleaseBodyBranches["release-it"] != ":italy:" || len(opts.ReleaseBodyBranches) != 1 {
+		t.Errorf("expected the branch map of the repo, got %v", opts.ReleaseBodyBranches)
+	}
+	if opts.BaseBranch != "main" {
+		t.Errorf("expected the base branch of the repo, got %q", opts.BaseBranch)
+	}
+	if opts.ReleaseBodyPrefix != "### Added" {
+		t.Errorf("expected the release body prefix of the repo name, got %q", opts.ReleaseBodyPrefix)
+	}
+}
+
+func TestInitConfigShouldLayerTheProfileOverTheConfig(t *testing.T) {
+	home := t.TempDir()
+	homeConfig := `github:
+  compare-workers: 2
+release:
+  body-template: "~/default.tmpl"
+profiles:
+  staging:
+    github:
+      compare-workers: 8
+`
+	if err := os.WriteFile(filepath.Join(home, ".ergo.yml"), []byte(homeConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+
+	vipOpts := NewOptions()
+	vipOpts.Path = home
+	vipOpts.Profile = "staging"
+	opts, err := vipOpts.InitConfig()
+	if err != nil {
+		t.Fatalf("InitConfig should not return the error: %v", err)
+	}
+	if opts.CompareWorkers != 8 {
+		t.Errorf("expected the compare workers of the profile, got %d", opts.CompareWorkers)
+	}
+	if opts.ReleaseBodyTemplate != "~/default.tmpl" {
+		t.Errorf("expected the body template of the config, got %q", opts.ReleaseBodyTemplate)
+	}
+
+	vipOpts = NewOptions()
+	vipOpts.Path = home
+	vipOpts.Profile = "missing"
+	if _, err := vipOpts.InitConfig(); err == nil || err.Error() != "profile missing not found" {
+		t.Errorf("expected the profile not found error, got %v", err)
+	}
+}
+
+func TestKeyShouldPreferTheProfileOverTheRepoOverrides(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("repos.ergo.release.calver-format", "YYYY.MM")
+	v.Set("repos.ergo.release.body-prefix", "### Repo")
+	defer func() {
+		v.Set("repos.ergo.release.calver-format", "")
+		v.Set("repos.ergo.release.body-prefix", "")
+	}()
+
+	vipOpts := NewOptions()
+	vipOpts.RepoName = "ergo"
+	vipOpts.addLayer("profile staging", []string{"release.calver-format"})
+
+	if key := vipOpts.key("release.calver-format"); key != "release.calver-format" {
+		t.Errorf("expected the key of the profile, got %s", key)
+	}
+	if key := vipOpts.key("release.body-prefix"); key != "repos.ergo.release.body-prefix" {
+		t.Errorf("expected the key of the repo, got %s", key)
+	}
+
+	vipOpts.addLayer("profile staging", []string{"repos.ergo.release.calver-format"})
+	if key := vipOpts.key("release.calver-format"); key != "repos.ergo.release.calver-format" {
+		t.Errorf("expected the repo key of the profile, got %s", key)
+	}
+}
+
+func TestRolloutWavesShouldDecodeTheWaves(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("release.rollout.waves", []interface{}{
+		map[string]interface{}{"name": "canary", "branches": []interface{}{"release-gr"}, "delay": "30m"},
+		map[string]interface{}{"name": "rest", "branches": []interface{}{"release-mx", "release-pe"}, "confirm": true},
+	})
+	defer v.Set("release.rollout.waves", nil)
+
+	waves, err := rolloutWaves("release.rollout.waves")
+	if err != nil {
+		t.Fatalf("rolloutWaves should not return the error: %v", err)
+	}
+	if len(waves) != 2 || waves[0].Name != "canary" || waves[0].Delay != 30*time.Minute || waves[0].Confirm {
+		t.Fatalf("unexpected waves %+v", waves)
+	}
+	if len(waves[1].Branches) != 2 || !waves[1].Confirm {
+		t.Errorf("unexpected second wave %+v", waves[1])
+	}
+}
+
+func TestRolloutWavesShouldRejectInvalidWaves(t *testing.T) {
+	tests := map[string][]interface{}{
+		"no name":     {map[string]interface{}{"branches": []interface{}{"release-gr"}}},
+		"no branches": {map[string]interface{}{"name": "canary"}},
+		"twice": {
+			map[string]interface{}{"name": "canary", "branches": []interface{}{"release-gr"}},
+			map[string]interface{}{"name": "canary", "branches": []interface{}{"release-mx"}},
+		},
+		"negative delay": {map[string]interface{}{"name": "canary", "branches": []interface{}{"release-gr"}, "delay": "-5m"}},
+		"branch in two waves": {
+			map[string]interface{}{"name": "canary", "branches": []interface{}{"release-gr"}},
+			map[string]interface{}{"name": "rest", "branches": []interface{}{"release-mx", "release-gr"}},
+		},
+	}
+	v := viper.GetViper()
+	defer v.Set("release.rollout.waves", nil)
+	for name, waves := range tests {
+		t.Run(name, func(t *testing.T) {
+			v.Set("release.rollout.waves", waves)
+			if _, err := rolloutWaves("release.rollout.waves"); err == nil {
+				t.Error("expected rolloutWaves to return an error")
+			}
+		})
+	}
+}
+
+func TestSetScheduleConfigsShouldReadTheFreezeCalendarInTheTimeZone(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	v := viper.GetViper()
+	v.Set("release.schedule.time-zone", "Europe/Athens")
+	v.Set("release.schedule.board-time-zones", []string{"Europe/Athens", "America/Mexico_City"})
+	v.Set("release.freeze.windows", []interface{}{
+		map[string]interface{}{"start": "2026-11-27T00:00", "end": "2026-11-30T00:00", "reason": "Black Friday"},
+	})
+	defer func() {
+		v.Set("release.schedule.time-zone", nil)
+		v.Set("release.schedule.board-time-zones", nil)
+		v.Set("release.freeze.windows", nil)
+	}()
+
+	vipOpts := NewOptions()
+	if err = vipOpts.setScheduleConfigs(); err != nil {
+		t.Fatalf("setScheduleConfigs should not return the error: %v", err)
+	}
+	if vipOpts.ScheduleLocation.String() != "Europe/Athens" || len(vipOpts.BoardLocations) != 2 {
+		t.Errorf("unexpected time zones %v and %v", vipOpts.ScheduleLocation, vipOpts.BoardLocations)
+	}
+	if len(vipOpts.FreezeCalendar.Windows) != 1 {
+		t.Fatalf("expected one freeze window, got %v", vipOpts.FreezeCalendar.Windows)
+	}
+	window := vipOpts.FreezeCalendar.Windows[0]
+	if !window.Start.Equal(time.Date(2026, 11, 27, 0, 0, 0, 0, athens)) || window.Reason != "Black Friday" {
+		t.Errorf("unexpected freeze window %+v", window)
+	}
+
+	v.Set("release.freeze.windows", []interface{}{
+		map[string]interface{}{"start": "2026-11-30T00:00", "end": "2026-11-27T00:00"},
+	})
+	if err = vipOpts.setScheduleConfigs(); err == nil {
+		t.Error("expected setScheduleConfigs to reject a window ending before it starts")
+	}
+	v.Set("release.schedule.time-zone", "Mars/Olympus")
+	if err = vipOpts.setScheduleConfigs(); err == nil {
+		t.Error("expected setScheduleConfigs to reject an unknown time zone")
+	}
+}
+
+func TestSetScheduleConfigsShouldReadTheWeeklyAndBranchFreezes(t *testing.T) {
+	v := viper.GetViper()
+	v.Set("release.freeze.weekly", []interface{}{
+		map[string]interface{}{"start": "Fri 14:00", "end": "monday 08:30", "reason": "weekend"},
+	})
+	v.Set("release.freeze.branches", map[string]interface{}{
+		"release-jp": map[string]interface{}{"ical": "~/jp-holidays.ics"},
+	})
+	defer func() {
+		v.Set("release.freeze.weekly", nil)
+		v.Set("release.freeze.branches", nil)
+	}()
+
+	vipOpts := NewOptions()
+	if err := vipOpts.setScheduleConfigs(); err != nil {
+		t.Fatalf("setScheduleConfigs should not return the error: %v", err)
+	}
+	weekly := vipOpts.FreezeCalendar.Weekly
+	if len(weekly) != 1 || weekly[0].StartDay != time.Friday || weekly[0].Start != 14*time.Hour ||
+		weekly[0].EndDay != time.Monday || weekly[0].End != 8*time.Hour+30*time.Minute {
+		t.Errorf("unexpected weekly freeze windows %+v", weekly)
+	}
+	jp, ok := vipOpts.BranchFreezeCalendars["release-jp"]
+	if !ok || jp.ICalFile != "~/jp-holidays.ics" || len(jp.Weekly) != 0 {
+		t.Errorf("unexpected freeze calendars of the branches %+v", vipOpts.BranchFreezeCalendars)
+	}
+
+	v.Set("release.freeze.weekly", []interface{}{map[string]interface{}{"start": "Someday 14:00", "end": "Mon 08:00"}})
+	if err := vipOpts.setScheduleConfigs(); err == nil {
+		t.Error("expected setScheduleConfigs to reject an unknown day")
+	}
+}
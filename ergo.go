@@ -8,24 +8,42 @@ import (
 // MessageLevel defines the level of output message.
 type MessageLevel string
 
+// CheckState defines the state of a CI check.
+type CheckState string
+
+const (
+	// CheckStatePending is the state of a check which has not completed yet.
+	CheckStatePending CheckState = "pending"
+	// CheckStateSuccess is the state of a check which has succeeded.
+	CheckStateSuccess CheckState = "success"
+	// CheckStateFailure is the state of a check which has failed.
+	CheckStateFailure CheckState = "failure"
+)
+
 // Host interface describes the host's actions.
 type Host interface {
 	CreateDraftRelease(ctx context.Context, name, tagName, releaseBody, targetBranch string) error
 	LastRelease(ctx context.Context) (*Release, error)
+	GetReleaseByTag(ctx context.Context, tagName string) (*Release, error)
+	ListReleases(ctx context.Context) ([]*Release, error)
 	EditRelease(ctx context.Context, release *Release) (*Release, error)
 	PublishRelease(ctx context.Context, releaseID int64) error
 	CompareBranch(ctx context.Context, baseBranch, branch string) (*StatusReport, error)
 	DiffCommits(ctx context.Context, releaseBranches []string, baseBranch string) ([]*StatusReport, error)
 	CreateTag(ctx context.Context, versionName, sha, m string) (*Tag, error)
 	UpdateBranchFromTag(ctx context.Context, tag, toBranch string, force bool) error
+	UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error
 	GetRef(ctx context.Context, branch string) (*Reference, error)
 	GetRefFromTag(ctx context.Context, tag string) (*Reference, error)
 	GetRepoName() string
+	WaitForChecks(ctx context.Context, sha string, pollInterval time.Duration) (*ChecksReport, error)
+	ListPullRequestsWithCommit(ctx context.Context, sha string) ([]*PullRequest, error)
 }
 
 // CLI describes the command line interface actions.
 type CLI interface {
 	PrintTable(header []string, values [][]string)
+	PrintObject(v interface{})
 	PrintColorizedLine(title, content string, level MessageLevel)
 	PrintLine(content ...interface{})
 	Confirmation(actionText, cancellationMessage, successMessage string) (bool, error)
@@ -59,15 +77,98 @@ type Release struct {
 
 // StatusReport struct is responsible to keep the information about current status.
 type StatusReport struct {
-	Branch     string
-	BaseBranch string
-	Ahead      []*Commit
-	Behind     []*Commit
+	Branch     string    `json:"branch" yaml:"branch"`
+	BaseBranch string    `json:"base_branch" yaml:"base_branch"`
+	Ahead      []*Commit `json:"ahead" yaml:"ahead"`
+	Behind     []*Commit `json:"behind" yaml:"behind"`
+	// AheadBy and BehindBy are the number of commits the branch is ahead and behind of the base
+	// branch, which may be more than the commits listed if the comparison is Truncated.
+	AheadBy   int  `json:"ahead_by" yaml:"ahead_by"`
+	BehindBy  int  `json:"behind_by" yaml:"behind_by"`
+	Truncated bool `json:"truncated" yaml:"truncated"`
 }
 
 // Commit describes the commit entity.
 type Commit struct {
-	Message string
+	SHA     string    `json:"sha" yaml:"sha"`
+	Author  string    `json:"author" yaml:"author"`
+	Date    time.Time `json:"date" yaml:"date"`
+	Message string    `json:"message" yaml:"message"`
+}
+
+// PullRequest describes a merged pull request.
+type PullRequest struct {
+	Number int
+	Title  string
+	Author string
+	URL    string
+	Labels []string
+}
+
+// ChecksReport describes the combined CI checks of a commit.
+type ChecksReport struct {
+	SHA    string
+	State  CheckState
+	Checks []*Check
+}
+
+// Check describes a single commit status or check run.
+type Check struct {
+	Name  string
+	State CheckState
+	URL   string
+}
+
+// NewChecksReport creates the report of the commit's checks. The report fails if any check has
+// failed, succeeds if all checks have succeeded and is pending otherwise, also without checks,
+// since the checks of a commit may not have been reported yet.
+func NewChecksReport(sha string, checks []*Check) *ChecksReport {
+	state := CheckStateSuccess
+	if len(checks) == 0 {
+		state = CheckStatePending
+	}
+	for _, check := range checks {
+		if check.State == CheckStateFailure {
+			state = CheckStateFailure
+			break
+		}
+		if check.State == CheckStatePending {
+			state = CheckStatePending
+		}
+	}
+	return &ChecksReport{SHA: sha, State: state, Checks: checks}
+}
+
+// PollChecks fetches the checks report every poll interval until it is no longer pending. The last
+// report is returned with the error of the context when the context ends.
+func PollChecks(
+	ctx context.Context,
+	t Time,
+	pollInterval time.Duration,
+	fetch func(ctx context.Context) (*ChecksReport, error),
+) (*ChecksReport, error) {
+	var last *ChecksReport
+	for {
+		report, err := fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return nil, err
+		}
+		if report.State != CheckStatePending {
+			return report, nil
+		}
+		last = report
+
+		if ctx.Err() != nil {
+			return last, ctx.Err()
+		}
+		t.Sleep(pollInterval)
+		if ctx.Err() != nil {
+			return last, ctx.Err()
+		}
+	}
 }
 
 // Tag describes the tag entity.
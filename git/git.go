@@ -0,0 +1,640 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+)
+
+const (
+	draftsRefPrefix   = "refs/ergo/drafts/"
+	releasesRefPrefix = "refs/ergo/releases/"
+)
+
+// RepositoryClient is a host backed by a local git repository. Releases are stored as annotated
+// tag objects under refs/ergo/drafts and refs/ergo/releases, with the release body as message.
+type RepositoryClient struct {
+	path   string
+	remote string
+	auth   transport.AuthMethod
+	repo   *gogit.Repository
+}
+
+// Open opens the git repository (bare or not) found at the path.
+func Open(path string) (*gogit.Repository, error) {
+	repo, err := gogit.PlainOpenWithOptions(path, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("error opening git repository %s: %w", path, err)
+	}
+	return repo, nil
+}
+
+// NewRepositoryClient instantiate a RepositoryClient. Every change is pushed to the remote
+// unless it is empty. The access token is only used for remotes over http(s).
+func NewRepositoryClient(path, remote, accessToken string, repo *gogit.Repository) *RepositoryClient {
+	rc := &RepositoryClient{
+		path:   path,
+		remote: remote,
+		repo:   repo,
+	}
+	if accessToken != "" {
+		rc.auth = &http.BasicAuth{Username: "ergo", Password: accessToken}
+	}
+	return rc
+}
+
+// CreateDraftRelease creates a draft release on the head of the target branch.
+func (gc *RepositoryClient) CreateDraftRelease(ctx context.Context, name, tagName, releaseBody, targetBranch string) error {
+	target, err := gc.resolveBranch(targetBranch)
+	if err != nil {
+		return err
+	}
+
+	refName := plumbing.ReferenceName(draftsRefPrefix + tagName)
+	saved, err := gc.saveRefs(refName)
+	if err != nil {
+		return err
+	}
+	if err = gc.storeRelease(refName, name, releaseBody, target); err != nil {
+		return err
+	}
+
+	return gc.pushOrRestore(ctx, saved, refSpec(refName, refName, true))
+}
+
+// LastRelease fetches the most recently created release, draft or not.
+func (gc *RepositoryClient) LastRelease(ctx context.Context) (*ergo.Release, error) {
+	var last *ergo.Release
+	var lastTime time.Time
+
+	err := gc.forEachRelease(func(ref *plumbing.Reference, tag *object.Tag, release *ergo.Release) error {
+		if last == nil || tag.Tagger.When.After(lastTime) {
+			last, lastTime = release, tag.Tagger.When
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return nil, errors.New("latest release not found")
+	}
+
+	return last, nil
+}
+
+// GetReleaseByTag returns the release of the tag. It returns nil if the tag has no release.
+func (gc *RepositoryClient) GetReleaseByTag(ctx context.Context, tagName string) (*ergo.Release, error) {
+	var found *ergo.Release
+	err := gc.forEachRelease(func(ref *plumbing.Reference, tag *object.Tag, release *ergo.Release) error {
+		if found == nil && release.TagName == tagName {
+			found = release
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// ListReleases returns the releases of the repository, latest first.
+func (gc *RepositoryClient) ListReleases(ctx context.Context) ([]*ergo.Release, error) {
+	var releases []*ergo.Release
+	times := make(map[*ergo.Release]time.Time)
+
+	err := gc.forEachRelease(func(ref *plumbing.Reference, tag *object.Tag, release *ergo.Release) error {
+		releases = append(releases, release)
+		times[release] = tag.Tagger.When
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return times[releases[i]].After(times[releases[j]])
+	})
+	return releases, nil
+}
+
+// EditRelease allows to edit a repository release.
+func (gc *RepositoryClient) EditRelease(ctx context.Context, release *ergo.Release) (*ergo.Release, error) {
+	if release == nil {
+		return nil, errors.New("nothing to release: input release is nil")
+	}
+
+	ref, tag, err := gc.findRelease(func(r *ergo.Release) bool { return r.ID == release.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	saved := []savedRef{{name: ref.Name(), ref: ref}}
+	if err = gc.storeRelease(ref.Name(), tag.Name, release.Body, tag.Target); err != nil {
+		return nil, err
+	}
+	if err = gc.pushOrRestore(ctx, saved, refSpec(ref.Name(), ref.Name(), true)); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// PublishRelease creates the release tag and moves the release out of the drafts.
+func (gc *RepositoryClient) PublishRelease(ctx context.Context, releaseID int64) error {
+	ref, tag, err := gc.findRelease(func(r *ergo.Release) bool { return r.ID == releaseID })
+	if err != nil {
+		return fmt.Errorf("publishing release (ID=%d): %w", releaseID, err)
+	}
+	if !strings.HasPrefix(ref.Name().String(), draftsRefPrefix) {
+		return fmt.Errorf("publishing release (ID=%d): release is not a draft", releaseID)
+	}
+
+	tagName := strings.TrimPrefix(ref.Name().String(), draftsRefPrefix)
+	tagRef := plumbing.ReferenceName("refs/tags/" + tagName)
+	releaseRefName := plumbing.ReferenceName(releasesRefPrefix + tagName)
+	saved, err := gc.saveRefs(tagRef, releaseRefName, ref.Name())
+	if err != nil {
+		return fmt.Errorf("publishing release (ID=%d): %w", releaseID, err)
+	}
+	if _, err = gc.repo.Reference(tagRef, false); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		_, err = gc.repo.CreateTag(tagName, tag.Target, &gogit.CreateTagOptions{
+			Tagger:  gc.signature(),
+			Message: tag.Name,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("publishing release (ID=%d): %w", releaseID, err)
+	}
+
+	releaseRef := plumbing.NewHashReference(releaseRefName, ref.Hash())
+	if err = gc.repo.Storer.SetReference(releaseRef); err != nil {
+		return err
+	}
+	if err = gc.repo.Storer.RemoveReference(ref.Name()); err != nil {
+		return err
+	}
+
+	return gc.pushOrRestore(ctx, saved,
+		refSpec(tagRef, tagRef, false),
+		refSpec(releaseRef.Name(), releaseRef.Name(), true),
+		config.RefSpec(":"+ref.Name().String()),
+	)
+}
+
+// CompareBranch compare the base branch with the given one.
+func (gc *RepositoryClient) CompareBranch(ctx context.Context, baseBranch, branch string) (*ergo.StatusReport, error) {
+	baseHash, err := gc.resolveBranch(baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	branchHash, err := gc.resolveBranch(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	commitsAhead, err := gc.commitsDiff(baseHash, branchHash)
+	if err != nil {
+		return nil, err
+	}
+
+	commitsBehind, err := gc.commitsDiff(branchHash, baseHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ergo.StatusReport{
+		Branch:     branch,
+		BaseBranch: baseBranch,
+		Ahead:      commitsAhead,
+		Behind:     commitsBehind,
+		AheadBy:    len(commitsAhead),
+		BehindBy:   len(commitsBehind),
+	}, nil
+}
+
+// DiffCommits is responsible to find the diff-commits and return a StatusReport for each of
+// given releaseBranches.
+func (gc *RepositoryClient) DiffCommits(ctx context.Context, releaseBranches []string, baseBranch string) ([]*ergo.StatusReport, error) {
+	var statusReports []*ergo.StatusReport
+	for _, branch := range releaseBranches {
+		statusReport, err := gc.CompareBranch(ctx, baseBranch, branch)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing base branch %s %s:%s", baseBranch, branch, err)
+		}
+		statusReports = append(statusReports, statusReport)
+	}
+	return statusReports, nil
+}
+
+// CreateTag creates an annotated tag and pushes it to the remote.
+func (gc *RepositoryClient) CreateTag(ctx context.Context, versionName, sha, m string) (*ergo.Tag, error) {
+	if m == "" {
+		m = versionName
+	}
+	saved, err := gc.saveRefs(plumbing.NewTagReferenceName(versionName))
+	if err != nil {
+		return nil, fmt.Errorf("error on tag creation: %v", err)
+	}
+	ref, err := gc.repo.CreateTag(versionName, plumbing.NewHash(sha), &gogit.CreateTagOptions{
+		Tagger:  gc.signature(),
+		Message: m,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error on tag creation: %v", err)
+	}
+
+	if err = gc.pushOrRestore(ctx, saved, refSpec(ref.Name(), ref.Name(), false)); err != nil {
+		return nil, fmt.Errorf("error on tag creation: %v", err)
+	}
+
+	return &ergo.Tag{Name: versionName}, nil
+}
+
+// UpdateBranchFromTag moves the branch to the commit of the tag. Without force the branch must
+// be an ancestor of the tag's commit.
+func (gc *RepositoryClient) UpdateBranchFromTag(ctx context.Context, tag, toBranch string, force bool) error {
+	target, err := gc.resolveTag(tag)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return fmt.Errorf("error on update branch from tag: tag %s not found", tag)
+	}
+
+	if err = gc.updateBranch(ctx, *target, toBranch, force); err != nil {
+		return fmt.Errorf("error on update branch from tag: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateBranchToSHA moves the branch to the commit. Without force the branch must be an
+// ancestor of the commit.
+func (gc *RepositoryClient) UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error {
+	if err := gc.updateBranch(ctx, plumbing.NewHash(sha), toBranch, force); err != nil {
+		return fmt.Errorf("error on update branch to %s: %v", sha, err)
+	}
+
+	return nil
+}
+
+// updateBranch sets the branch to the commit and pushes it to the remote. The push needs the
+// local branch to be set first, so the branch is restored if the push fails.
+func (gc *RepositoryClient) updateBranch(ctx context.Context, target plumbing.Hash, toBranch string, force bool) error {
+	if !force {
+		if err := gc.checkFastForward(toBranch, target); err != nil {
+			return err
+		}
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(toBranch)
+	saved, err := gc.saveRefs(branchRef)
+	if err != nil {
+		return err
+	}
+	if err = gc.repo.Storer.SetReference(plumbing.NewHashReference(branchRef, target)); err != nil {
+		return err
+	}
+
+	return gc.pushOrRestore(ctx, saved, refSpec(branchRef, branchRef, force))
+}
+
+// GetRef branch reference object given the branch name.
+func (gc *RepositoryClient) GetRef(ctx context.Context, branch string) (*ergo.Reference, error) {
+	hash, err := gc.resolveBranch(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ergo.Reference{SHA: hash.String(), Ref: plumbing.NewBranchReferenceName(branch).String()}, nil
+}
+
+// GetRefFromTag get reference from tag.
+func (gc *RepositoryClient) GetRefFromTag(ctx context.Context, tag string) (*ergo.Reference, error) {
+	hash, err := gc.resolveTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	if hash == nil {
+		return nil, nil
+	}
+
+	return &ergo.Reference{SHA: hash.String(), Ref: plumbing.NewTagReferenceName(tag).String()}, nil
+}
+
+// GetRepoName return the repository name.
+func (gc *RepositoryClient) GetRepoName() string {
+	abs, err := filepath.Abs(gc.path)
+	if err != nil {
+		return gc.path
+	}
+	return filepath.Base(abs)
+}
+
+// ListPullRequestsWithCommit returns no pull requests, since a plain repository has none.
+func (gc *RepositoryClient) ListPullRequestsWithCommit(ctx context.Context, sha string) ([]*ergo.PullRequest, error) {
+	return nil, nil
+}
+
+// WaitForChecks reports success without checks, since a local repository has no CI.
+func (gc *RepositoryClient) WaitForChecks(ctx context.Context, sha string, pollInterval time.Duration) (*ergo.ChecksReport, error) {
+	return &ergo.ChecksReport{SHA: sha, State: ergo.CheckStateSuccess}, nil
+}
+
+// resolveBranch resolves the commit of a local branch, or of the remote tracking branch.
+func (gc *RepositoryClient) resolveBranch(branch string) (plumbing.Hash, error) {
+	names := []plumbing.ReferenceName{plumbing.NewBranchReferenceName(branch)}
+	if gc.remote != "" {
+		names = append(names, plumbing.NewRemoteReferenceName(gc.remote, branch))
+	}
+
+	for _, name := range names {
+		ref, err := gc.repo.Reference(name, true)
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			continue
+		}
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return ref.Hash(), nil
+	}
+
+	return plumbing.ZeroHash, fmt.Errorf("branch %s not found", branch)
+}
+
+// resolveTag resolves the commit a tag points to. It returns nil if the tag does not exist.
+func (gc *RepositoryClient) resolveTag(tag string) (*plumbing.Hash, error) {
+	ref, err := gc.repo.Tag(tag)
+	if errors.Is(err, gogit.ErrTagNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag reference %v", err)
+	}
+
+	hash := ref.Hash()
+	tagObject, err := gc.repo.TagObject(hash)
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return &hash, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag reference %v", err)
+	}
+
+	c, err := tagObject.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag reference %v", err)
+	}
+
+	return &c.Hash, nil
+}
+
+// checkFastForward checks that the branch head is an ancestor of the target commit.
+func (gc *RepositoryClient) checkFastForward(branch string, target plumbing.Hash) error {
+	head, err := gc.resolveBranch(branch)
+	if err != nil {
+		return err
+	}
+
+	ancestors, err := gc.ancestors(target)
+	if err != nil {
+		return err
+	}
+	if _, ok := ancestors[head]; !ok {
+		return fmt.Errorf("branch %s has diverged", branch)
+	}
+
+	return nil
+}
+
+// commitsDiff returns the commits reachable from the branch but not from the base.
+func (gc *RepositoryClient) commitsDiff(base, branch plumbing.Hash) ([]*ergo.Commit, error) {
+	baseAncestors, err := gc.ancestors(base)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := gc.repo.Log(&gogit.LogOptions{From: branch})
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*ergo.Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if _, ok := baseAncestors[c.Hash]; !ok {
+			commits = append(commits, &ergo.Commit{
+				SHA:     c.Hash.String(),
+				Author:  c.Author.Name,
+				Date:    c.Author.When,
+				Message: c.Message,
+			})
+		}
+		return nil
+	})
+
+	return commits, err
+}
+
+// ancestors returns the set of commits reachable from the given commit, itself included.
+func (gc *RepositoryClient) ancestors(from plumbing.Hash) (map[plumbing.Hash]struct{}, error) {
+	iter, err := gc.repo.Log(&gogit.LogOptions{From: from})
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[plumbing.Hash]struct{})
+	err = iter.ForEach(func(c *object.Commit) error {
+		set[c.Hash] = struct{}{}
+		return nil
+	})
+
+	return set, err
+}
+
+// storeRelease writes the release as tag object and points the reference to it.
+func (gc *RepositoryClient) storeRelease(refName plumbing.ReferenceName, name, body string, target plumbing.Hash) error {
+	tag := &object.Tag{
+		Name:       name,
+		Tagger:     *gc.signature(),
+		Message:    body,
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+
+	obj := gc.repo.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return err
+	}
+	hash, err := gc.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return err
+	}
+
+	return gc.repo.Storer.SetReference(plumbing.NewHashReference(refName, hash))
+}
+
+// findRelease returns the first release matching the predicate.
+func (gc *RepositoryClient) findRelease(match func(*ergo.Release) bool) (*plumbing.Reference, *object.Tag, error) {
+	var foundRef *plumbing.Reference
+	var foundTag *object.Tag
+
+	err := gc.forEachRelease(func(ref *plumbing.Reference, tag *object.Tag, release *ergo.Release) error {
+		if foundRef == nil && match(release) {
+			foundRef, foundTag = ref, tag
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if foundRef == nil {
+		return nil, nil, errors.New("release not found")
+	}
+
+	return foundRef, foundTag, nil
+}
+
+// forEachRelease calls fn for every stored release.
+func (gc *RepositoryClient) forEachRelease(fn func(*plumbing.Reference, *object.Tag, *ergo.Release) error) error {
+	refs, err := gc.repo.References()
+	if err != nil {
+		return err
+	}
+
+	return refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		var tagName string
+		var draft bool
+		switch {
+		case strings.HasPrefix(name, draftsRefPrefix):
+			tagName, draft = strings.TrimPrefix(name, draftsRefPrefix), true
+		case strings.HasPrefix(name, releasesRefPrefix):
+			tagName = strings.TrimPrefix(name, releasesRefPrefix)
+		default:
+			return nil
+		}
+
+		tag, err := gc.repo.TagObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("error reading release %s: %w", name, err)
+		}
+
+		return fn(ref, tag, &ergo.Release{
+			ID:         releaseIDFromTag(tagName),
+			Body:       tag.Message,
+			TagName:    tagName,
+			ReleaseURL: name,
+			Draft:      draft,
+		})
+	})
+}
+
+// push pushes the ref specs to the remote, if one is configured.
+func (gc *RepositoryClient) push(ctx context.Context, specs ...config.RefSpec) error {
+	if gc.remote == "" {
+		return nil
+	}
+
+	err := gc.repo.PushContext(ctx, &gogit.PushOptions{
+		RemoteName: gc.remote,
+		RefSpecs:   specs,
+		Auth:       gc.auth,
+	})
+	if errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil
+	}
+
+	return err
+}
+
+// savedRef is a local reference saved before it is changed, with a nil ref if it did not exist.
+type savedRef struct {
+	name plumbing.ReferenceName
+	ref  *plumbing.Reference
+}
+
+// saveRefs saves the local references before they are changed.
+func (gc *RepositoryClient) saveRefs(names ...plumbing.ReferenceName) ([]savedRef, error) {
+	saved := make([]savedRef, 0, len(names))
+	for _, name := range names {
+		ref, err := gc.repo.Storer.Reference(name)
+		if err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return nil, err
+		}
+		saved = append(saved, savedRef{name: name, ref: ref})
+	}
+	return saved, nil
+}
+
+// pushOrRestore pushes the ref specs to the remote. If the push fails, the saved references are
+// restored, or removed if they did not exist, so that the local repository matches the remote
+// and the change can be retried.
+func (gc *RepositoryClient) pushOrRestore(ctx context.Context, saved []savedRef, specs ...config.RefSpec) error {
+	err := gc.push(ctx, specs...)
+	if err == nil {
+		return nil
+	}
+
+	for _, s := range saved {
+		var errRestore error
+		if s.ref != nil {
+			errRestore = gc.repo.Storer.SetReference(s.ref)
+		} else {
+			errRestore = gc.repo.Storer.RemoveReference(s.name)
+		}
+		if errRestore != nil {
+			return fmt.Errorf("%v, and restoring the local %s failed: %v", err, s.name.Short(), errRestore)
+		}
+	}
+	return err
+}
+
+// signature returns the signature of the configured git user.
+func (gc *RepositoryClient) signature() *object.Signature {
+	sig := &object.Signature{Name: "ergo", Email: "ergo@localhost", When: time.Now()}
+
+	cfg, err := gc.repo.Config()
+	if err != nil {
+		return sig
+	}
+	user := cfg.Raw.Section("user")
+	if name := user.Option("name"); name != "" {
+		sig.Name = name
+	}
+	if email := user.Option("email"); email != "" {
+		sig.Email = email
+	}
+
+	return sig
+}
+
+// refSpec builds the ref spec to push src to dst.
+func refSpec(src, dst plumbing.ReferenceName, force bool) config.RefSpec {
+	spec := src.String() + ":" + dst.String()
+	if force {
+		spec = "+" + spec
+	}
+	return config.RefSpec(spec)
+}
+
+// releaseIDFromTag derives a stable numeric release ID from the tag name.
+func releaseIDFromTag(tagName string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(tagName))
+	return int64(h.Sum64() & (1<<63 - 1))
+}
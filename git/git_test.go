@@ -0,0 +1,378 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// setup creates a repository with the commits "a" and "b" on master and "c" on branch
+// release, which is forked from "a".
+func setup(t *testing.T) (repo *gogit.Repository, path string, shas map[string]string) {
+	t.Helper()
+	path = t.TempDir()
+
+	repo, err := gogit.PlainInit(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shas = make(map[string]string)
+	commit := func(msg string) {
+		if err = os.WriteFile(filepath.Join(path, msg), []byte(msg), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = wt.Add(msg); err != nil {
+			t.Fatal(err)
+		}
+		hash, errCommit := wt.Commit(msg, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()},
+		})
+		if errCommit != nil {
+			t.Fatal(errCommit)
+		}
+		shas[msg] = hash.String()
+	}
+
+	commit("a")
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), head.Hash()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit("b")
+
+	if err = wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release")}); err != nil {
+		t.Fatal(err)
+	}
+	commit("c")
+	if err = wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo, path, shas
+}
+
+func TestOpenShouldReturnErrorForMissingRepository(t *testing.T) {
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("Open should return error when there is no repository")
+	}
+}
+
+func TestCompareBranchShouldCompareBranches(t *testing.T) {
+	repo, path, _ := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	got, err := repClient.CompareBranch(context.Background(), "master", "release")
+	if err != nil {
+		t.Fatalf("CompareBranch should not return the error: %v", err)
+	}
+	if len(got.Ahead) != 1 || got.Ahead[0].Message != "c" {
+		t.Errorf("got ahead %v; want [c]", got.Ahead)
+	}
+	if len(got.Behind) != 1 || got.Behind[0].Message != "b" {
+		t.Errorf("got behind %v; want [b]", got.Behind)
+	}
+}
+
+func TestCompareBranchShouldReturnErrorForMissingBranch(t *testing.T) {
+	repo, path, _ := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	if _, err := repClient.CompareBranch(context.Background(), "master", "missing"); err == nil {
+		t.Error("CompareBranch should return error for a missing branch")
+	}
+}
+
+func TestCreateTagShouldCreateAnAnnotatedTag(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	tag, err := repClient.CreateTag(ctx, "1.0.0", shas["b"], "")
+	if err != nil {
+		t.Fatalf("CreateTag should not return the error: %v", err)
+	}
+	if tag.Name != "1.0.0" {
+		t.Errorf("got = %v; want %v", tag.Name, "1.0.0")
+	}
+
+	ref, err := repClient.GetRefFromTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.SHA != shas["b"] {
+		t.Errorf("tag points to %v; want %v", ref.SHA, shas["b"])
+	}
+}
+
+func TestGetRefFromTagShouldReturnNilForMissingTag(t *testing.T) {
+	repo, path, _ := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	got, err := repClient.GetRefFromTag(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("Should not return error for missing tag, error: %v", err)
+	}
+	if got != nil {
+		t.Error("Should return nil for missing tag")
+	}
+}
+
+func TestUpdateBranchFromTagShouldRejectDivergedBranchWithoutForce(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	if _, err := repClient.CreateTag(ctx, "1.0.0", shas["b"], ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repClient.UpdateBranchFromTag(ctx, "1.0.0", "release", false); err == nil {
+		t.Error("UpdateBranchFromTag should return error for a diverged branch")
+	}
+	if err := repClient.UpdateBranchFromTag(ctx, "1.0.0", "release", true); err != nil {
+		t.Fatalf("UpdateBranchFromTag should not return the error with force: %v", err)
+	}
+
+	ref, err := repClient.GetRef(ctx, "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.SHA != shas["b"] {
+		t.Errorf("branch points to %v; want %v", ref.SHA, shas["b"])
+	}
+}
+
+func TestDraftReleaseShouldBePublished(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	if err := repClient.CreateDraftRelease(ctx, "name", "1.0.0", "body", "master"); err != nil {
+		t.Fatalf("CreateDraftRelease should not return the error: %v", err)
+	}
+
+	release, err := repClient.LastRelease(ctx)
+	if err != nil {
+		t.Fatalf("LastRelease should not return the error: %v", err)
+	}
+	if !release.Draft || release.TagName != "1.0.0" || release.Body != "body" {
+		t.Fatalf("unexpected release %v", release)
+	}
+
+	release.Body = "new body"
+	if _, err = repClient.EditRelease(ctx, release); err != nil {
+		t.Fatalf("EditRelease should not return the error: %v", err)
+	}
+	if err = repClient.PublishRelease(ctx, release.ID); err != nil {
+		t.Fatalf("PublishRelease should not return the error: %v", err)
+	}
+
+	release, err = repClient.LastRelease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Draft || release.Body != "new body" {
+		t.Errorf("unexpected release %v", release)
+	}
+
+	ref, err := repClient.GetRefFromTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref == nil || ref.SHA != shas["b"] {
+		t.Errorf("published tag %v; want it on %v", ref, shas["b"])
+	}
+}
+
+func TestUpdateBranchFromTagShouldPushToTheRemote(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+
+	remotePath := t.TempDir()
+	remoteRepo, err := gogit.PlainInit(remotePath, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{remotePath}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Push(&gogit.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/*:refs/heads/*"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	repClient := NewRepositoryClient(path, "origin", "", repo)
+	if _, err = repClient.CreateTag(ctx, "1.0.0", shas["b"], ""); err != nil {
+		t.Fatal(err)
+	}
+	if err = repClient.UpdateBranchFromTag(ctx, "1.0.0", "release", true); err != nil {
+		t.Fatalf("UpdateBranchFromTag should not return the error: %v", err)
+	}
+
+	ref, err := remoteRepo.Reference(plumbing.NewBranchReferenceName("release"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Hash().String() != shas["b"] {
+		t.Errorf("remote branch points to %v; want %v", ref.Hash(), shas["b"])
+	}
+}
+
+func TestUpdateBranchToSHAShouldRestoreTheLocalBranchWhenThePushFails(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+
+	_, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{filepath.Join(t.TempDir(), "missing")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repClient := NewRepositoryClient(path, "origin", "", repo)
+	if err = repClient.UpdateBranchToSHA(ctx, shas["b"], "release", true); err == nil {
+		t.Fatal("UpdateBranchToSHA should return the error of the push")
+	}
+
+	ref, err := repClient.GetRef(ctx, "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.SHA != shas["c"] {
+		t.Errorf("local branch points to %v; want it restored to %v", ref.SHA, shas["c"])
+	}
+}
+
+func TestCreateTagShouldRemoveTheLocalTagWhenThePushFails(t *testing.T) {
+	ctx := context.Background()
+	repo, path, shas := setup(t)
+
+	_, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{filepath.Join(t.TempDir(), "missing")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repClient := NewRepositoryClient(path, "origin", "", repo)
+	if _, err = repClient.CreateTag(ctx, "1.0.0", shas["b"], ""); err == nil {
+		t.Fatal("CreateTag should return the error of the push")
+	}
+
+	ref, err := repClient.GetRefFromTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref != nil {
+		t.Errorf("expected the local tag to be removed, got %v", ref)
+	}
+}
+
+func TestReleaseChangesShouldBeRestoredWhenThePushFails(t *testing.T) {
+	ctx := context.Background()
+	repo, path, _ := setup(t)
+
+	if err := NewRepositoryClient(path, "", "", repo).CreateDraftRelease(ctx, "name", "1.0.0", "body", "master"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{filepath.Join(t.TempDir(), "missing")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repClient := NewRepositoryClient(path, "origin", "", repo)
+	release, err := repClient.LastRelease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := *release
+	edited.Body = "new body"
+	if _, err = repClient.EditRelease(ctx, &edited); err == nil {
+		t.Fatal("EditRelease should return the error of the push")
+	}
+	if err = repClient.PublishRelease(ctx, release.ID); err == nil {
+		t.Fatal("PublishRelease should return the error of the push")
+	}
+
+	got, err := repClient.LastRelease(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Draft || got.Body != "body" {
+		t.Errorf("expected the draft to be restored, got %+v", got)
+	}
+	ref, err := repClient.GetRefFromTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref != nil {
+		t.Errorf("expected the local tag to be removed, got %v", ref)
+	}
+	if _, err = repo.Reference(plumbing.ReferenceName(releasesRefPrefix+"1.0.0"), false); err == nil {
+		t.Error("expected the local release to be removed")
+	}
+}
+
+func TestGetRepoNameShouldReturnTheDirectoryName(t *testing.T) {
+	repClient := NewRepositoryClient("/tmp/my-repo", "", "", nil)
+
+	if got := repClient.GetRepoName(); got != "my-repo" {
+		t.Errorf("got = %v; want %v", got, "my-repo")
+	}
+}
+
+func TestGetReleaseByTagShouldFindTheRelease(t *testing.T) {
+	ctx := context.Background()
+	repo, path, _ := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	if err := repClient.CreateDraftRelease(ctx, "name", "1.0.0", "body", "master"); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := repClient.GetReleaseByTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatalf("GetReleaseByTag should not return the error: %v", err)
+	}
+	if release == nil || release.TagName != "1.0.0" || release.Body != "body" {
+		t.Errorf("unexpected release %v", release)
+	}
+
+	if release, err = repClient.GetReleaseByTag(ctx, "2.0.0"); err != nil || release != nil {
+		t.Errorf("expected no release and no error, got %v, %v", release, err)
+	}
+}
+
+func TestListReleasesShouldReturnTheLatestFirst(t *testing.T) {
+	ctx := context.Background()
+	repo, path, _ := setup(t)
+	repClient := NewRepositoryClient(path, "", "", repo)
+
+	for _, tag := range []string{"1.0.0", "1.1.0"} {
+		if err := repClient.CreateDraftRelease(ctx, tag, tag, "body", "master"); err != nil {
+			t.Fatal(err)
+		}
+		// tag objects only keep the time in seconds
+		time.Sleep(time.Second)
+	}
+
+	got, err := repClient.ListReleases(ctx)
+	if err != nil {
+		t.Fatalf("ListReleases should not return the error: %v", err)
+	}
+	if len(got) != 2 || got[0].TagName != "1.1.0" || got[1].TagName != "1.0.0" {
+		t.Errorf("unexpected releases %v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/google/go-github/v41/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// appJWTLifetime is the lifetime of the JWTs authenticating as the app, GitHub allows up to 10m.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockDrift backdates the JWTs to allow for clock drift with GitHub.
+	appJWTClockDrift = time.Minute
+	// installationTokenEarlyRefresh is how long before it expires an installation token is refreshed,
+	// so that it does not expire during a request.
+	installationTokenEarlyRefresh = time.Minute
+)
+
+// NewInstallationTokenSource returns the token source of the installation tokens of a GitHub App.
+// The tokens are requested from the API at the base URL, api.github.com if empty, with JWTs signed
+// by the PEM encoded private key of the app, and refreshed before they expire.
+func NewInstallationTokenSource(
+	appID, installationID int64,
+	privateKey []byte,
+	baseURL string,
+	transport http.RoundTripper,
+	t ergo.Time,
+) (oauth2.TokenSource, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	httpClient := &http.Client{Transport: &appTransport{appID: appID, key: key, base: transport, time: t}}
+	client := github.NewClient(httpClient)
+	if baseURL != "" {
+		client, err = github.NewEnterpriseClient(baseURL, baseURL, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("error creating github enterprise client: %w", err)
+		}
+	}
+
+	return oauth2.ReuseTokenSource(nil, &installationTokenSource{client: client, installationID: installationID}), nil
+}
+
+// installationTokenSource requests a new installation token on every call.
+type installationTokenSource struct {
+	client         *github.Client
+	installationID int64
+}
+
+// Token requests an installation token, which expires in an hour.
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.client.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating installation token of installation %d: %w", s.installationID, err)
+	}
+	oauthToken := &oauth2.Token{AccessToken: token.GetToken(), TokenType: "token"}
+	if expiresAt := token.GetExpiresAt(); !expiresAt.IsZero() {
+		oauthToken.Expiry = expiresAt.Add(-installationTokenEarlyRefresh)
+	}
+	return oauthToken, nil
+}
+
+// appTransport authenticates the requests as the GitHub App with a new JWT each.
+type appTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+	base  http.RoundTripper
+	time  ergo.Time
+}
+
+// RoundTrip sends the request with the JWT of the app.
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := signAppJWT(t.appID, t.key, t.time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(req)
+}
+
+// signAppJWT returns the RS256 JWT issued by the app at the given time.
+func signAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing app JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses the PEM encoded PKCS#1 or PKCS#8 RSA private key of the app.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing app private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("app private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
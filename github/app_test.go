@@ -0,0 +1,168 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo/mock"
+)
+
+var (
+	testAppKeyOnce sync.Once
+	testAppKey     *rsa.PrivateKey
+)
+
+// appKey returns the RSA key of the test app, generated once for all the tests.
+func appKey(t *testing.T) *rsa.PrivateKey {
+	testAppKeyOnce.Do(func() {
+		var err error
+		testAppKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	return testAppKey
+}
+
+func appKeyPEM(t *testing.T) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(appKey(t))})
+}
+
+// verifyAppJWT verifies the signature of the JWT and returns its claims.
+func verifyAppJWT(t *testing.T, jwt string) map[string]interface{} {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("invalid JWT %q", jwt)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&appKey(t).PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("invalid JWT signature: %v", err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestInstallationTokenSourceShouldExchangeTheJWTForAnInstallationToken(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/99/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		claims := verifyAppJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if claims["iss"] != "42" || claims["exp"].(float64) != float64(now.Add(appJWTLifetime).Unix()) {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		fmt.Fprintf(w, `{"token": "installation-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ts, err := NewInstallationTokenSource(42, 99, appKeyPEM(t), server.URL, nil, mock.NewMockedTime(now))
+	if err != nil {
+		t.Fatalf("NewInstallationTokenSource should not return the error: %v", err)
+	}
+
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token should not return the error: %v", err)
+	}
+	if token.AccessToken != "installation-token" {
+		t.Errorf("unexpected token %q", token.AccessToken)
+	}
+}
+
+func TestInstallationTokenSourceShouldRefreshExpiringTokens(t *testing.T) {
+	calls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/99/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// expires within the early refresh, so it has to be refreshed for the next request
+		expiresAt := time.Now().Add(installationTokenEarlyRefresh / 2)
+		if calls > 1 {
+			expiresAt = time.Now().Add(time.Hour)
+		}
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, calls, expiresAt.Format(time.RFC3339))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ts, err := NewInstallationTokenSource(42, 99, appKeyPEM(t), server.URL, nil, mock.NewMockedTime(time.Now()))
+	if err != nil {
+		t.Fatalf("NewInstallationTokenSource should not return the error: %v", err)
+	}
+
+	var tokens []string
+	for i := 0; i < 3; i++ {
+		token, err := ts.Token()
+		if err != nil {
+			t.Fatalf("Token should not return the error: %v", err)
+		}
+		tokens = append(tokens, token.AccessToken)
+	}
+	if strings.Join(tokens, ",") != "token-1,token-2,token-2" {
+		t.Errorf("expected the expiring token to be refreshed once, got %v", tokens)
+	}
+}
+
+func TestInstallationTokenSourceShouldReturnTheExchangeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message": "A JSON web token could not be decoded"}`)
+	}))
+	defer server.Close()
+
+	ts, err := NewInstallationTokenSource(42, 99, appKeyPEM(t), server.URL, nil, mock.NewMockedTime(time.Now()))
+	if err != nil {
+		t.Fatalf("NewInstallationTokenSource should not return the error: %v", err)
+	}
+
+	if _, err := ts.Token(); err == nil {
+		t.Fatal("Token should return the error of the exchange")
+	}
+}
+
+func TestNewInstallationTokenSourceShouldReturnErrorForInvalidKey(t *testing.T) {
+	_, err := NewInstallationTokenSource(42, 99, []byte("not a key"), "", nil, mock.NewMockedTime(time.Now()))
+	if err == nil {
+		t.Fatal("NewInstallationTokenSource should return the error for an invalid key")
+	}
+}
+
+func TestParsePrivateKeyShouldParsePKCS8Keys(t *testing.T) {
+	der, err := x509.MarshalPKCS8PrivateKey(appKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := parsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("parsePrivateKey should not return the error: %v", err)
+	}
+	if !key.Equal(appKey(t)) {
+		t.Error("expected the parsed key to equal the encoded one")
+	}
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/beatlabs/ergo"
+)
+
+const (
+	// defaultCompareWorkers is the number of branches compared concurrently if not configured.
+	defaultCompareWorkers = 4
+)
+
+// BranchErrors aggregates the errors of comparing multiple branches.
+type BranchErrors struct {
+	BaseBranch string
+	Errors     map[string]error
+	branches   []string
+}
+
+// Error lists the errors of the branches in the order they were compared.
+func (e *BranchErrors) Error() string {
+	messages := make([]string, 0, len(e.branches))
+	for _, branch := range e.branches {
+		messages = append(messages, fmt.Sprintf("%s: %v", branch, e.Errors[branch]))
+	}
+	return fmt.Sprintf("error comparing base branch %s with %s", e.BaseBranch, strings.Join(messages, "; "))
+}
+
+// SetCompareWorkers sets how many branches DiffCommits compares concurrently.
+func (gc *RepositoryClient) SetCompareWorkers(workers int) {
+	gc.compareWorkers = workers
+}
+
+// DiffCommits compares the release branches with the base branch concurrently and returns a
+// StatusReport for each one, in the order of the branches. If some comparisons fail, the reports
+// of the others are returned along with a *BranchErrors.
+func (gc *RepositoryClient) DiffCommits(ctx context.Context, releaseBranches []string, baseBranch string) ([]*ergo.StatusReport, error) {
+	workers := gc.compareWorkers
+	if workers <= 0 {
+		workers = defaultCompareWorkers
+	}
+
+	reports := make([]*ergo.StatusReport, len(releaseBranches))
+	errs := make([]error, len(releaseBranches))
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for i, branch := range releaseBranches {
+		wg.Add(1)
+		go func(i int, branch string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			reports[i], errs[i] = gc.CompareBranch(ctx, baseBranch, branch)
+		}(i, branch)
+	}
+	wg.Wait()
+
+	var statusReports []*ergo.StatusReport
+	var branchErrors *BranchErrors
+	for i, branch := range releaseBranches {
+		if errs[i] == nil {
+			statusReports = append(statusReports, reports[i])
+			continue
+		}
+		if branchErrors == nil {
+			branchErrors = &BranchErrors{BaseBranch: baseBranch, Errors: make(map[string]error)}
+		}
+		branchErrors.branches = append(branchErrors.branches, branch)
+		branchErrors.Errors[branch] = errs[i]
+	}
+	if branchErrors != nil {
+		return statusReports, branchErrors
+	}
+	return statusReports, nil
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo/mock"
+	"github.com/google/go-github/v41/github"
+)
+
+func TestDiffCommitsShouldCompareConcurrentlyInStableOrder(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	branches := []string{"b1", "b2", "b3", "b4", "b5", "b6"}
+	var running, maxRunning int32
+	for i, branch := range branches {
+		delay := time.Duration(len(branches)-i) * time.Millisecond
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(delay)
+			fmt.Fprint(w, `{ "commits": [] }`)
+		}
+		mux.HandleFunc("/repos/o/r/compare/base..."+branch, handler)
+		mux.HandleFunc("/repos/o/r/compare/"+branch+"...base", handler)
+	}
+
+	repClient := NewRepositoryClient("o", "r", client)
+	repClient.SetCompareWorkers(2)
+
+	got, err := repClient.DiffCommits(ctx, branches, "base")
+	if err != nil {
+		t.Fatalf("DiffCommits should not return the error: %v", err)
+	}
+	for i, report := range got {
+		if report.Branch != branches[i] {
+			t.Errorf("got branch %s at %d; want %s", report.Branch, i, branches[i])
+		}
+	}
+	if maxRunning > 2 {
+		t.Errorf("got %d concurrent comparisons; want at most 2", maxRunning)
+	}
+}
+
+func TestDiffCommitsShouldAggregateTheBranchErrors(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	ok := func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, `{ "commits": [] }`) }
+	notFound := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) }
+	mux.HandleFunc("/repos/o/r/compare/base...b1", notFound)
+	mux.HandleFunc("/repos/o/r/compare/base...b2", ok)
+	mux.HandleFunc("/repos/o/r/compare/b2...base", ok)
+	mux.HandleFunc("/repos/o/r/compare/base...b3", notFound)
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.DiffCommits(ctx, []string{"b1", "b2", "b3"}, "base")
+
+	var branchErrors *BranchErrors
+	if !errors.As(err, &branchErrors) {
+		t.Fatalf("DiffCommits should return the branch errors, got: %v", err)
+	}
+	if len(branchErrors.Errors) != 2 || branchErrors.Errors["b1"] == nil || branchErrors.Errors["b3"] == nil {
+		t.Errorf("unexpected branch errors %v", branchErrors.Errors)
+	}
+	if len(got) != 1 || got[0].Branch != "b2" {
+		t.Errorf("expected the report of b2, got %v", got)
+	}
+}
+
+func TestDiffCommitsShouldRetryAfterTheSecondaryRateLimit(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+	baseURL := client.BaseURL
+	client = github.NewClient(&http.Client{Transport: NewTransport(nil, mock.NewMockedTime(transportStart))})
+	client.BaseURL = baseURL
+
+	var mu sync.Mutex
+	calls := 0
+	mux.HandleFunc("/repos/o/r/compare/base...b1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+		if first {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{ "message": "secondary rate limit",
+				"documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits" }`)
+			return
+		}
+		fmt.Fprint(w, `{ "commits": [] }`)
+	})
+	mux.HandleFunc("/repos/o/r/compare/b1...base", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "commits": [] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.DiffCommits(ctx, []string{"b1"}, "base")
+	if err != nil {
+		t.Fatalf("DiffCommits should not return the error: %v", err)
+	}
+	if len(got) != 1 || calls != 2 {
+		t.Errorf("got %d reports after %d calls; want 1 after 2", len(got), calls)
+	}
+}
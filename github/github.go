@@ -5,27 +5,61 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/beatlabs/ergo"
+	ergoTime "github.com/beatlabs/ergo/time"
 	"github.com/google/go-github/v41/github"
 	"golang.org/x/oauth2"
 )
 
 // RepositoryClient for Github API.
 type RepositoryClient struct {
-	organization string
-	repo         string
-	client       *github.Client
+	organization   string
+	repo           string
+	client         *github.Client
+	compareWorkers int
+	time           ergo.Time
 }
 
-// NewGithubClient set up a github client.
-func NewGithubClient(ctx context.Context, accessToken string) *github.Client {
-	ts := oauth2.StaticTokenSource(
+// NewGithubClient set up a github client authenticating with the tokens of the token source. The
+// requests are sent with the transport, if not nil.
+func NewGithubClient(ctx context.Context, ts oauth2.TokenSource, transport http.RoundTripper) *github.Client {
+	return github.NewClient(oauthClient(ctx, ts, transport))
+}
+
+// NewGithubEnterpriseClient set up a client of a GitHub Enterprise Server. The upload URL defaults
+// to the base URL, and the api/v3/ and api/uploads/ paths are appended to them if missing.
+func NewGithubEnterpriseClient(
+	ctx context.Context,
+	baseURL, uploadURL string,
+	ts oauth2.TokenSource,
+	transport http.RoundTripper,
+) (*github.Client, error) {
+	if uploadURL == "" {
+		uploadURL = baseURL
+	}
+	client, err := github.NewEnterpriseClient(baseURL, uploadURL, oauthClient(ctx, ts, transport))
+	if err != nil {
+		return nil, fmt.Errorf("error creating github enterprise client: %w", err)
+	}
+	return client, nil
+}
+
+// NewTokenSource returns the token source of a personal access token.
+func NewTokenSource(accessToken string) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: accessToken},
 	)
-	tc := oauth2.NewClient(ctx, ts)
+}
 
-	return github.NewClient(tc)
+// oauthClient returns an http client authenticating with the tokens of the token source.
+func oauthClient(ctx context.Context, ts oauth2.TokenSource, transport http.RoundTripper) *http.Client {
+	if transport != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+	}
+	return oauth2.NewClient(ctx, ts)
 }
 
 // NewRepositoryClient instantiate a RepositoryClient.
@@ -34,9 +68,15 @@ func NewRepositoryClient(organization, repo string, client *github.Client) *Repo
 		organization: organization,
 		repo:         repo,
 		client:       client,
+		time:         ergoTime.Time{},
 	}
 }
 
+// SetTime sets the time used to wait between the polls of the checks.
+func (gc *RepositoryClient) SetTime(t ergo.Time) {
+	gc.time = t
+}
+
 // CreateDraftRelease creates a draft release.
 func (gc *RepositoryClient) CreateDraftRelease(ctx context.Context, name, tagName, releaseBody, targetBranch string) error {
 	isDraft := true
@@ -79,6 +119,51 @@ func (gc *RepositoryClient) LastRelease(ctx context.Context) (*ergo.Release, err
 	}, nil
 }
 
+// GetReleaseByTag fetches the release of the tag, drafts excluded. It returns nil if the tag has
+// no release.
+func (gc *RepositoryClient) GetReleaseByTag(ctx context.Context, tagName string) (*ergo.Release, error) {
+	githubRelease, _, err := gc.client.Repositories.GetReleaseByTag(ctx, gc.organization, gc.repo, tagName)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting release of tag %s: %w", tagName, err)
+	}
+
+	return &ergo.Release{
+		ID:         githubRelease.GetID(),
+		Body:       githubRelease.GetBody(),
+		TagName:    githubRelease.GetTagName(),
+		ReleaseURL: githubRelease.GetHTMLURL(),
+		Draft:      githubRelease.GetDraft(),
+	}, nil
+}
+
+// ListReleases returns the releases of the repository, latest first.
+func (gc *RepositoryClient) ListReleases(ctx context.Context) ([]*ergo.Release, error) {
+	var releases []*ergo.Release
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		githubReleases, resp, err := gc.client.Repositories.ListReleases(ctx, gc.organization, gc.repo, opt)
+		if err != nil {
+			return nil, fmt.Errorf("error listing releases: %w", err)
+		}
+		for _, r := range githubReleases {
+			releases = append(releases, &ergo.Release{
+				ID:         r.GetID(),
+				Body:       r.GetBody(),
+				TagName:    r.GetTagName(),
+				ReleaseURL: r.GetHTMLURL(),
+				Draft:      r.GetDraft(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return releases, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
 // EditRelease allows to edit a repository release.
 func (gc *RepositoryClient) EditRelease(ctx context.Context, release *ergo.Release) (*ergo.Release, error) {
 	if release == nil {
@@ -159,47 +244,65 @@ func (gc *RepositoryClient) CreateTag(ctx context.Context, versionName, sha, m s
 
 // CompareBranch compare the base branch with the given one.
 func (gc *RepositoryClient) CompareBranch(ctx context.Context, baseBranch, branch string) (*ergo.StatusReport, error) {
-	commitsAhead, err := gc.commitsDiff(ctx, baseBranch, branch)
+	commitsAhead, comparison, err := gc.commitsDiff(ctx, baseBranch, branch)
 	if err != nil {
 		return nil, err
 	}
 
-	commitsBehind, err := gc.commitsDiff(ctx, branch, baseBranch)
+	commitsBehind, reverseComparison, err := gc.commitsDiff(ctx, branch, baseBranch)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ergo.StatusReport{Branch: branch, BaseBranch: baseBranch, Ahead: commitsAhead, Behind: commitsBehind}, nil
+	return &ergo.StatusReport{
+		Branch:     branch,
+		BaseBranch: baseBranch,
+		Ahead:      commitsAhead,
+		Behind:     commitsBehind,
+		AheadBy:    comparison.GetAheadBy(),
+		BehindBy:   comparison.GetBehindBy(),
+		Truncated: len(commitsAhead) < comparison.GetTotalCommits() ||
+			len(commitsBehind) < reverseComparison.GetTotalCommits(),
+	}, nil
 }
 
-// commitsDiff finds the differences in commits between two branches.
-func (gc *RepositoryClient) commitsDiff(ctx context.Context, baseBranch, branch string) ([]*ergo.Commit, error) {
-	comparison, _, err := gc.client.Repositories.CompareCommits(ctx, gc.organization, gc.repo, baseBranch, branch, &github.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
+// commitsDiff finds the differences in commits between two branches, paging through all the
+// commits of the comparison. It also returns the comparison of the first page, which holds the
+// commit counts. GitHub may still list fewer commits than the total for very large comparisons.
+func (gc *RepositoryClient) commitsDiff(
+	ctx context.Context,
+	baseBranch, branch string,
+) ([]*ergo.Commit, *github.CommitsComparison, error) {
+	opts := &github.ListOptions{PerPage: 100}
 
+	var first *github.CommitsComparison
 	var commitsAhead []*ergo.Commit
-	for _, commit := range comparison.Commits {
-		commitAhead := &ergo.Commit{Message: *commit.Commit.Message}
-		commitsAhead = append(commitsAhead, commitAhead)
-	}
+	for {
+		comparison, resp, err := gc.client.Repositories.CompareCommits(ctx, gc.organization, gc.repo, baseBranch, branch, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first == nil {
+			first = comparison
+		}
 
-	return commitsAhead, nil
-}
+		for _, commit := range comparison.Commits {
+			commitAhead := &ergo.Commit{
+				SHA:     commit.GetSHA(),
+				Author:  commit.GetCommit().GetAuthor().GetName(),
+				Date:    commit.GetCommit().GetAuthor().GetDate(),
+				Message: commit.GetCommit().GetMessage(),
+			}
+			commitsAhead = append(commitsAhead, commitAhead)
+		}
 
-// DiffCommits is responsible to find the diff-commits and return a StatusReport for each of
-// given releaseBranches.
-func (gc *RepositoryClient) DiffCommits(ctx context.Context, releaseBranches []string, baseBranch string) ([]*ergo.StatusReport, error) {
-	var statusReports []*ergo.StatusReport
-	for _, branch := range releaseBranches {
-		statusReport, err := gc.CompareBranch(ctx, baseBranch, branch)
-		if err != nil {
-			return nil, fmt.Errorf("error comparing base branch %s %s:%s", baseBranch, branch, err)
+		if resp.NextPage == 0 {
+			break
 		}
-		statusReports = append(statusReports, statusReport)
+		opts.Page = resp.NextPage
 	}
-	return statusReports, nil
+
+	return commitsAhead, first, nil
 }
 
 // UpdateBranchFromTag is responsible to update a branch from tag.
@@ -219,6 +322,18 @@ func (gc *RepositoryClient) UpdateBranchFromTag(ctx context.Context, tag, toBran
 	return nil
 }
 
+// UpdateBranchToSHA moves the branch to the commit.
+func (gc *RepositoryClient) UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error {
+	branchRef := "heads/" + toBranch
+	ref := &github.Reference{Ref: &branchRef, Object: &github.GitObject{SHA: &sha}}
+	_, _, err := gc.client.Git.UpdateRef(ctx, gc.organization, gc.repo, ref, force)
+	if err != nil {
+		return fmt.Errorf("error on update branch to %s: %v", sha, err)
+	}
+
+	return nil
+}
+
 // GetRefFromTag get reference from tag.
 func (gc *RepositoryClient) GetRefFromTag(ctx context.Context, tag string) (*ergo.Reference, error) {
 	ref, err := gc.getRefFromGitHub(ctx, tag)
@@ -250,3 +365,121 @@ func (gc *RepositoryClient) getRefFromGitHub(ctx context.Context, tag string) (*
 func (gc *RepositoryClient) GetRepoName() string {
 	return gc.organization + "/" + gc.repo
 }
+
+// TokenScopes returns the OAuth scopes of the token reading the repository. It reports false if
+// the token has no scopes, e.g. a fine-grained token or the installation token of an app.
+func (gc *RepositoryClient) TokenScopes(ctx context.Context) ([]string, bool, error) {
+	_, resp, err := gc.client.Repositories.Get(ctx, gc.organization, gc.repo)
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading repository %s: %w", gc.GetRepoName(), err)
+	}
+
+	header, ok := resp.Header["X-Oauth-Scopes"]
+	if !ok || len(header) == 0 {
+		return nil, false, nil
+	}
+	var scopes []string
+	for _, scope := range strings.Split(header[0], ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true, nil
+}
+
+// ListPullRequestsWithCommit returns the merged pull requests associated with the commit.
+func (gc *RepositoryClient) ListPullRequestsWithCommit(ctx context.Context, sha string) ([]*ergo.PullRequest, error) {
+	githubPulls, _, err := gc.client.PullRequests.ListPullRequestsWithCommit(ctx, gc.organization, gc.repo, sha,
+		&github.PullRequestListOptions{ListOptions: github.ListOptions{PerPage: 100}})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pull requests of commit %s: %w", sha, err)
+	}
+
+	var pulls []*ergo.PullRequest
+	for _, p := range githubPulls {
+		if p.MergedAt == nil {
+			continue
+		}
+		pull := &ergo.PullRequest{
+			Number: p.GetNumber(),
+			Title:  p.GetTitle(),
+			Author: p.GetUser().GetLogin(),
+			URL:    p.GetHTMLURL(),
+		}
+		for _, label := range p.Labels {
+			pull.Labels = append(pull.Labels, label.GetName())
+		}
+		pulls = append(pulls, pull)
+	}
+	return pulls, nil
+}
+
+// WaitForChecks polls the combined status and the check runs of the commit until all of them
+// have completed or one of them has failed. The last report is returned when the context ends.
+func (gc *RepositoryClient) WaitForChecks(ctx context.Context, sha string, pollInterval time.Duration) (*ergo.ChecksReport, error) {
+	return ergo.PollChecks(ctx, gc.time, pollInterval, func(ctx context.Context) (*ergo.ChecksReport, error) {
+		return gc.checks(ctx, sha)
+	})
+}
+
+// checks fetches the commit statuses and the check runs of the commit, page by page.
+func (gc *RepositoryClient) checks(ctx context.Context, sha string) (*ergo.ChecksReport, error) {
+	var checks []*ergo.Check
+
+	statusOpts := &github.ListOptions{PerPage: 100}
+	for {
+		combinedStatus, resp, err := gc.client.Repositories.GetCombinedStatus(ctx, gc.organization, gc.repo, sha, statusOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error getting combined status: %w", err)
+		}
+		for _, status := range combinedStatus.Statuses {
+			state := ergo.CheckStateFailure
+			switch status.GetState() {
+			case "success":
+				state = ergo.CheckStateSuccess
+			case "pending":
+				state = ergo.CheckStatePending
+			}
+			checks = append(checks, &ergo.Check{Name: status.GetContext(), State: state, URL: status.GetTargetURL()})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	runOpts := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		checkRuns, resp, err := gc.client.Checks.ListCheckRunsForRef(ctx, gc.organization, gc.repo, sha, runOpts)
+		if isNotFound(err) {
+			// GitHub Enterprise Server instances without the checks API only report commit statuses.
+			return ergo.NewChecksReport(sha, checks), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing check runs: %w", err)
+		}
+		for _, run := range checkRuns.CheckRuns {
+			state := ergo.CheckStateFailure
+			switch {
+			case run.GetStatus() != "completed":
+				state = ergo.CheckStatePending
+			case run.GetConclusion() == "success", run.GetConclusion() == "neutral", run.GetConclusion() == "skipped":
+				state = ergo.CheckStateSuccess
+			}
+			checks = append(checks, &ergo.Check{Name: run.GetName(), State: state, URL: run.GetHTMLURL()})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		runOpts.Page = resp.NextPage
+	}
+
+	return ergo.NewChecksReport(sha, checks), nil
+}
+
+// isNotFound reports whether the error is a not found response of the API.
+func isNotFound(err error) bool {
+	var errorResponse *github.ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.Response != nil &&
+		errorResponse.Response.StatusCode == http.StatusNotFound
+}
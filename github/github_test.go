@@ -9,8 +9,10 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
 	"github.com/google/go-github/v41/github"
 )
 
@@ -50,12 +52,33 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 
 func TestNewGithubClient(t *testing.T) {
 	ctx := context.Background()
-	client := NewGithubClient(ctx, "access_token")
+	client := NewGithubClient(ctx, NewTokenSource("access_token"), nil)
 	if client == nil {
 		t.Fatalf("Client should not be nil")
 	}
 }
 
+func TestNewGithubEnterpriseClientShouldUseTheEnterpriseEndpoints(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewGithubEnterpriseClient(ctx, "https://github.example.com", "", NewTokenSource("access_token"), nil)
+	if err != nil {
+		t.Fatalf("NewGithubEnterpriseClient should not return the error: %v", err)
+	}
+	if got := client.BaseURL.String(); got != "https://github.example.com/api/v3/" {
+		t.Errorf("unexpected base URL %q", got)
+	}
+	if got := client.UploadURL.String(); got != "https://github.example.com/api/uploads/" {
+		t.Errorf("unexpected upload URL %q", got)
+	}
+}
+
+func TestNewGithubEnterpriseClientShouldReturnErrorForInvalidURL(t *testing.T) {
+	_, err := NewGithubEnterpriseClient(context.Background(), "://github.example.com", "", NewTokenSource("access_token"), nil)
+	if err == nil {
+		t.Fatal("NewGithubEnterpriseClient should return the error for an invalid URL")
+	}
+}
+
 func TestNewRepositoryClientShouldReturnANewObject(t *testing.T) {
 	client, _, teardown := setup()
 	defer teardown()
@@ -120,6 +143,68 @@ func TestCompareBranchShouldCompareBranches(t *testing.T) {
 	}
 }
 
+func TestCompareBranchShouldPageThroughTheComparison(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/compare/base...branch", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{ "ahead_by": 3, "behind_by": 1, "total_commits": 3, "commits": [{ "commit": { "message": "baz" } }] }`)
+			return
+		}
+		w.Header().Set("Link", `<https://api.github.com/repos/o/r/compare/base...branch?page=2>; rel="next"`)
+		fmt.Fprint(w, `{ "ahead_by": 3, "behind_by": 1, "total_commits": 3, "commits": [{ "commit": { "message": "foo" } }, { "commit": { "message": "bar" } }] }`)
+	})
+
+	mux.HandleFunc("/repos/o/r/compare/branch...base", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "ahead_by": 1, "behind_by": 3, "total_commits": 1, "commits": [{ "commit": { "message": "qux" } }] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.CompareBranch(ctx, "base", "branch")
+	if err != nil {
+		t.Fatalf("CompareBranch should not return the error: %v", err)
+	}
+	if len(got.Ahead) != 3 || got.Ahead[2].Message != "baz" {
+		t.Fatalf("expected the commits of all the pages, got %d", len(got.Ahead))
+	}
+	if got.AheadBy != 3 || got.BehindBy != 1 {
+		t.Errorf("expected 3 ahead and 1 behind, got %d and %d", got.AheadBy, got.BehindBy)
+	}
+	if got.Truncated {
+		t.Error("expected the comparison not to be truncated")
+	}
+}
+
+func TestCompareBranchShouldFlagTruncatedComparison(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/compare/base...branch", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "ahead_by": 0, "behind_by": 300, "total_commits": 0, "commits": [] }`)
+	})
+
+	mux.HandleFunc("/repos/o/r/compare/branch...base", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "ahead_by": 300, "behind_by": 0, "total_commits": 300, "commits": [{ "commit": { "message": "foo" } }] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.CompareBranch(ctx, "base", "branch")
+	if err != nil {
+		t.Fatalf("CompareBranch should not return the error: %v", err)
+	}
+	if got.BehindBy != 300 || len(got.Behind) != 1 {
+		t.Errorf("expected 300 behind with 1 listed, got %d with %d listed", got.BehindBy, len(got.Behind))
+	}
+	if !got.Truncated {
+		t.Error("expected the comparison to be truncated")
+	}
+}
+
 func TestCompareBranchShouldReturnErrorForInvalidResposne(t *testing.T) {
 	ctx := context.Background()
 	client, mux, tearDown := setup()
@@ -195,6 +280,40 @@ func TestLastReleaseShouldReturnTheLastRelease(t *testing.T) {
 	}
 }
 
+func TestGetReleaseByTagShouldReturnTheReleaseOfTheTag(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	want := &ergo.Release{ID: 12, Body: "release_body", TagName: "1.2.0", ReleaseURL: "url"}
+
+	mux.HandleFunc("/repos/o/r/releases/tags/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `{ "id": %d, "body": "%s", "tag_name": "%s", "html_url": "%s" }`, want.ID, want.Body, want.TagName, want.ReleaseURL)
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).GetReleaseByTag(context.Background(), "1.2.0")
+	if err != nil {
+		t.Fatalf("GetReleaseByTag should not return the error: %v", err)
+	}
+	if got == nil || *got != *want {
+		t.Errorf("got = %v; want %v", got, *want)
+	}
+}
+
+func TestGetReleaseByTagShouldReturnNilForStatusNotFound(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/releases/tags/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).GetReleaseByTag(context.Background(), "1.2.0")
+	if err != nil || got != nil {
+		t.Errorf("expected no release and no error, got %v, %v", got, err)
+	}
+}
+
 func TestLastReleaseShouldReturnWrappedErrorForCodeStatusNotFound(t *testing.T) {
 	ctx := context.Background()
 	client, mux, tearDown := setup()
@@ -567,6 +686,24 @@ func TestUpdateBranchFromTagShouldUpdateBranchFromGivenTag(t *testing.T) {
 	}
 }
 
+func TestUpdateBranchToSHAShouldForceTheBranchToTheCommit(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/git/refs/heads/to_branch", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPatch)
+		testBody(t, r, `{"sha":"sha","force":true}`+"\n")
+		fmt.Fprint(w, "{}")
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	if err := repClient.UpdateBranchToSHA(ctx, "sha", "to_branch", true); err != nil {
+		t.Fatalf("Should not return the error: %v", err)
+	}
+}
+
 func TestUpdateBranchFromTagShouldReturnErrorForInvalidUpdateRefPayload(t *testing.T) {
 	ctx := context.Background()
 	client, mux, tearDown := setup()
@@ -726,3 +863,234 @@ func TestGetRepoNameShouldReturnTheRepoName(t *testing.T) {
 		t.Errorf("got = %s, want = %s", got, want)
 	}
 }
+
+func TestTokenScopesShouldReturnTheScopesOfTheToken(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		fmt.Fprint(w, `{"full_name": "o/r"}`)
+	})
+
+	scopes, ok, err := NewRepositoryClient("o", "r", client).TokenScopes(context.Background())
+	if err != nil {
+		t.Fatalf("Should not return the error: %v", err)
+	}
+	if !ok || len(scopes) != 2 || scopes[0] != "repo" || scopes[1] != "read:org" {
+		t.Errorf("unexpected scopes %v, %v", scopes, ok)
+	}
+}
+
+func TestTokenScopesShouldReportATokenWithoutScopes(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"full_name": "o/r"}`)
+	})
+
+	scopes, ok, err := NewRepositoryClient("o", "r", client).TokenScopes(context.Background())
+	if err != nil || ok || scopes != nil {
+		t.Errorf("expected no scopes, got %v, %v, %v", scopes, ok, err)
+	}
+}
+
+func TestWaitForChecksShouldPollUntilChecksComplete(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	polls := 0
+	mux.HandleFunc("/repos/o/r/commits/sha/status", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		fmt.Fprint(w, `{ "state": "success", "statuses": [{ "context": "ci", "state": "success" }] }`)
+	})
+	mux.HandleFunc("/repos/o/r/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "2" {
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+			fmt.Fprint(w, `{ "check_runs": [{ "name": "lint", "status": "completed", "conclusion": "success" }] }`)
+			return
+		}
+		if polls < 2 {
+			fmt.Fprint(w, `{ "check_runs": [{ "name": "build", "status": "in_progress" }] }`)
+			return
+		}
+		fmt.Fprint(w, `{ "check_runs": [{ "name": "build", "status": "completed", "conclusion": "success" }] }`)
+	})
+
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	clock := mock.NewMockedTime(start)
+	repClient := NewRepositoryClient("o", "r", client)
+	repClient.SetTime(clock)
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Minute)
+	if err != nil {
+		t.Fatalf("WaitForChecks should not return the error: %v", err)
+	}
+	if got.State != ergo.CheckStateSuccess || len(got.Checks) != 3 {
+		t.Errorf("unexpected report %+v", got)
+	}
+	if polls != 2 {
+		t.Errorf("expected 2 polls, got %d", polls)
+	}
+	if want := start.Add(time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("expected to wait until %v, got %v", want, clock.Now())
+	}
+}
+
+func TestWaitForChecksShouldWaitForTheChecksToBeReported(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	polls := 0
+	mux.HandleFunc("/repos/o/r/commits/sha/status", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		fmt.Fprint(w, `{ "state": "pending", "statuses": [] }`)
+	})
+	mux.HandleFunc("/repos/o/r/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if polls < 3 {
+			fmt.Fprint(w, `{ "check_runs": [] }`)
+			return
+		}
+		fmt.Fprint(w, `{ "check_runs": [{ "name": "build", "status": "completed", "conclusion": "success" }] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+	repClient.SetTime(mock.NewMockedTime(time.Now()))
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Minute)
+	if err != nil {
+		t.Fatalf("WaitForChecks should not return the error: %v", err)
+	}
+	if got.State != ergo.CheckStateSuccess || polls != 3 {
+		t.Errorf("expected the report after 3 polls, got %+v after %d", got, polls)
+	}
+}
+
+func TestWaitForChecksShouldOnlyUseStatusesWithoutTheChecksAPI(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/commits/sha/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "state": "success", "statuses": [{ "context": "ci", "state": "success" }] }`)
+	})
+	mux.HandleFunc("/repos/o/r/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{ "message": "Not Found" }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForChecks should not return the error: %v", err)
+	}
+	if got.State != ergo.CheckStateSuccess || len(got.Checks) != 1 {
+		t.Errorf("unexpected report %+v", got)
+	}
+}
+
+func TestWaitForChecksShouldReturnFailedChecks(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/commits/sha/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "state": "pending", "statuses": [] }`)
+	})
+	mux.HandleFunc("/repos/o/r/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "check_runs": [
+			{ "name": "build", "status": "completed", "conclusion": "failure" },
+			{ "name": "lint", "status": "queued" }
+		] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForChecks should not return the error: %v", err)
+	}
+	if got.State != ergo.CheckStateFailure {
+		t.Errorf("got state %v; want %v", got.State, ergo.CheckStateFailure)
+	}
+}
+
+func TestWaitForChecksShouldReturnTheReportWhenTheContextEnds(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/commits/sha/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "state": "pending", "statuses": [{ "context": "ci", "state": "pending" }] }`)
+	})
+	mux.HandleFunc("/repos/o/r/commits/sha/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "check_runs": [] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForChecks should return the context error, got: %v", err)
+	}
+	if got == nil || got.State != ergo.CheckStatePending {
+		t.Errorf("expected the pending report, got %+v", got)
+	}
+}
+
+func TestListReleasesShouldReturnAllPages(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{ "id": 1, "tag_name": "1.0.0" }]`)
+			return
+		}
+		w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+		fmt.Fprint(w, `[{ "id": 3, "tag_name": "1.2.0", "draft": true }, { "id": 2, "tag_name": "1.1.0" }]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.ListReleases(ctx)
+	if err != nil {
+		t.Fatalf("ListReleases should not return the error: %v", err)
+	}
+	if len(got) != 3 || got[0].TagName != "1.2.0" || !got[0].Draft || got[2].TagName != "1.0.0" {
+		t.Errorf("unexpected releases %v", got)
+	}
+}
+
+func TestListPullRequestsWithCommitShouldReturnTheMergedPullRequests(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/repos/o/r/commits/sha/pulls", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[
+			{ "number": 1, "title": "Add login", "user": { "login": "alice" }, "labels": [{ "name": "feature" }],
+			  "merged_at": "2026-10-17T09:00:00Z" },
+			{ "number": 2, "title": "Open", "user": { "login": "bob" } }
+		]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.ListPullRequestsWithCommit(ctx, "sha")
+	if err != nil {
+		t.Fatalf("ListPullRequestsWithCommit should not return the error: %v", err)
+	}
+	if len(got) != 1 || got[0].Number != 1 || got[0].Author != "alice" || len(got[0].Labels) != 1 {
+		t.Errorf("unexpected pull requests %v", got)
+	}
+}
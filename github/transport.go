@@ -0,0 +1,230 @@
+package github
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beatlabs/ergo"
+)
+
+const (
+	// defaultMaxRetries is how many times a failing request is retried if not configured.
+	defaultMaxRetries = 3
+	// defaultMinBackoff is the backoff before the first retry if not configured.
+	defaultMinBackoff = time.Second
+	// defaultMaxBackoff caps the exponential backoff if not configured.
+	defaultMaxBackoff = 30 * time.Second
+	// defaultMaxWait is the longest wait for a rate limit to reset if not configured.
+	defaultMaxWait = 2 * time.Minute
+)
+
+// RateLimit is the quota reported by GitHub in the X-RateLimit headers of a response.
+type RateLimit struct {
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Transport is an http.RoundTripper which retries the idempotent requests failing with a server
+// error or hitting a rate limit. It waits as long as GitHub asks with the Retry-After and
+// X-RateLimit-Reset headers, and otherwise with jittered exponential backoff.
+type Transport struct {
+	base       http.RoundTripper
+	time       ergo.Time
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	maxWait    time.Duration
+
+	mu          sync.Mutex
+	onRateLimit func(RateLimit)
+}
+
+// NewTransport instantiate a Transport sending the requests with the base round tripper, or the
+// default transport if it is nil.
+func NewTransport(base http.RoundTripper, t ergo.Time) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base:       base,
+		time:       t,
+		maxRetries: defaultMaxRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+		maxWait:    defaultMaxWait,
+	}
+}
+
+// SetRetries sets how many times a request is retried and the bounds of the backoff between the
+// retries. Non positive values keep the defaults, except maxRetries 0 which disables the retries.
+func (t *Transport) SetRetries(maxRetries int, minBackoff, maxBackoff time.Duration) {
+	if maxRetries >= 0 {
+		t.maxRetries = maxRetries
+	}
+	if minBackoff > 0 {
+		t.minBackoff = minBackoff
+	}
+	if maxBackoff > 0 {
+		t.maxBackoff = maxBackoff
+	}
+}
+
+// SetMaxWait sets the longest wait for a rate limit. Requests which would have to wait longer
+// return the rate limit error instead.
+func (t *Transport) SetMaxWait(maxWait time.Duration) {
+	if maxWait > 0 {
+		t.maxWait = maxWait
+	}
+}
+
+// SetRateLimitObserver sets the function called with the quota reported by every response.
+func (t *Transport) SetRateLimitObserver(fn func(RateLimit)) {
+	t.onRateLimit = fn
+}
+
+// RoundTrip sends the request, retrying it if it is idempotent and fails with a retryable error.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			t.observeRateLimit(resp)
+		}
+		if !retryable || attempt >= t.maxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait, retry := t.retryWait(resp, err, attempt)
+		if !retry || wait > t.maxWait {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if req.GetBody != nil {
+			body, errBody := req.GetBody()
+			if errBody != nil {
+				return nil, errBody
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		t.time.Sleep(wait)
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryWait returns how long to wait before retrying the request, or false if the failure is not
+// worth a retry.
+func (t *Transport) retryWait(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return t.backoff(attempt), true
+	}
+
+	if wait, ok := retryAfter(resp); ok && isRateLimited(resp) {
+		return wait, true
+	}
+	if isRateLimited(resp) && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, ok := rateLimitReset(resp)
+		if !ok {
+			return 0, false
+		}
+		if wait := reset.Sub(t.time.Now()); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if wait, ok := retryAfter(resp); ok {
+			return wait, true
+		}
+		return t.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff returns the exponential backoff of the attempt, randomized within its upper half.
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.minBackoff
+	for i := 0; i < attempt && d < t.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > t.maxBackoff {
+		d = t.maxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// observeRateLimit passes the quota of the response to the observer, if any.
+func (t *Transport) observeRateLimit(resp *http.Response) {
+	if t.onRateLimit == nil {
+		return
+	}
+	limit, errLimit := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if errLimit != nil || errRemaining != nil {
+		return
+	}
+	reset, _ := rateLimitReset(resp)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onRateLimit(RateLimit{
+		Resource:  resp.Header.Get("X-RateLimit-Resource"),
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	})
+}
+
+// isIdempotent reports whether the request can be sent again without side effects. The PATCH of
+// a ref or a release sets it to a fixed value, such as the SHA of a branch or the body of a
+// release, so it is safe to repeat too.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPatch:
+		return strings.Contains(req.URL.Path, "/git/refs/") || strings.Contains(req.URL.Path, "/releases/")
+	}
+	return false
+}
+
+// isRateLimited reports whether GitHub rejected the request because of a rate limit.
+func isRateLimited(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// retryAfter returns the wait of the Retry-After header in seconds, if any.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// rateLimitReset returns the time of the X-RateLimit-Reset header in epoch seconds, if any.
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+	epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(epoch, 0), true
+}
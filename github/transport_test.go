@@ -0,0 +1,295 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo/mock"
+)
+
+var transportStart = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func TestTransportShouldRetryServerErrorsWithBackoff(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	clock := mock.NewMockedTime(transportStart)
+	transport := NewTransport(nil, clock)
+	transport.SetRetries(3, time.Second, 4*time.Second)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("expected success after 3 calls, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	// the first backoff is within [0.5s, 1s] and the second within [1s, 2s]
+	if waited := clock.Now().Sub(transportStart); waited < 1500*time.Millisecond || waited > 3*time.Second {
+		t.Errorf("unexpected backoff %v", waited)
+	}
+}
+
+func TestTransportShouldStopAfterMaxRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, mock.NewMockedTime(transportStart))
+	transport.SetRetries(2, time.Second, time.Second)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 3 {
+		t.Errorf("expected the last failure after 3 calls, got status %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestTransportShouldNotRetryNonIdempotentRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, mock.NewMockedTime(transportStart))
+
+	resp, err := (&http.Client{Transport: transport}).Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Post should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("expected a single call, got %d", calls)
+	}
+}
+
+func TestTransportShouldReplayTheBodyOfRetriedRequests(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, mock.NewMockedTime(transportStart))
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"sha":"abc"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatalf("Do should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[1] != `{"sha":"abc"}` {
+		t.Errorf("expected the body to be sent twice, got %q", bodies)
+	}
+}
+
+func TestTransportShouldWaitForRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "42")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}))
+	defer server.Close()
+
+	clock := mock.NewMockedTime(transportStart)
+	transport := NewTransport(nil, clock)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Fatalf("expected success after 2 calls, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	if waited := clock.Now().Sub(transportStart); waited != 42*time.Second {
+		t.Errorf("expected to wait 42s, waited %v", waited)
+	}
+}
+
+func TestTransportShouldWaitForTheRateLimitReset(t *testing.T) {
+	calls := 0
+	reset := transportStart.Add(30 * time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}))
+	defer server.Close()
+
+	clock := mock.NewMockedTime(transportStart)
+	transport := NewTransport(nil, clock)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 2 || !clock.Now().Equal(reset) {
+		t.Errorf("expected a retry at %v, got %d calls at %v", reset, calls, clock.Now())
+	}
+}
+
+func TestTransportShouldNotWaitLongerThanMaxWait(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(transportStart.Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	transport := NewTransport(nil, mock.NewMockedTime(transportStart))
+	transport.SetMaxWait(time.Minute)
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden || calls != 1 {
+		t.Errorf("expected the rate limit error without retries, got status %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestTransportShouldObserveTheRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(transportStart.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+	}))
+	defer server.Close()
+
+	var got []RateLimit
+	transport := NewTransport(nil, mock.NewMockedTime(transportStart))
+	transport.SetRateLimitObserver(func(rl RateLimit) {
+		got = append(got, rl)
+	})
+
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get should not return the error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(got) != 1 {
+		t.Fatalf("expected one rate limit, got %d", len(got))
+	}
+	if got[0].Limit != 5000 || got[0].Remaining != 4321 || got[0].Resource != "core" || !got[0].Reset.Equal(transportStart) {
+		t.Errorf("unexpected rate limit %+v", got[0])
+	}
+}
+
+func TestNewGithubClientShouldSendRequestsThroughTheTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer access_token" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"tag_name": "v1.0.0"}`)
+	}))
+	defer server.Close()
+
+	client := NewGithubClient(context.Background(), NewTokenSource("access_token"), NewTransport(nil, mock.NewMockedTime(transportStart)))
+	client.BaseURL, _ = client.BaseURL.Parse(server.URL + "/")
+
+	release, _, err := client.Repositories.GetLatestRelease(context.Background(), "o", "r")
+	if err != nil {
+		t.Fatalf("GetLatestRelease should not return the error: %v", err)
+	}
+	if release.GetTagName() != "v1.0.0" || calls != 2 {
+		t.Errorf("expected the release after a retry, got %q after %d calls", release.GetTagName(), calls)
+	}
+}
+
+func TestTransportShouldRetryThePatchOfARef(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		wantCalls int
+	}{
+		"ref":     {path: "/repos/o/r/git/refs/heads/release-gr", wantCalls: 2},
+		"release": {path: "/repos/o/r/releases/1", wantCalls: 2},
+		"issue":   {path: "/repos/o/r/issues/1", wantCalls: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPatch, server.URL+tt.path, strings.NewReader(`{"sha": "abc"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := (&http.Client{Transport: NewTransport(nil, mock.NewMockedTime(transportStart))}).Do(req)
+			if err != nil {
+				t.Fatalf("Do should not return the error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
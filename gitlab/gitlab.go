@@ -0,0 +1,571 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	ergoTime "github.com/beatlabs/ergo/time"
+)
+
+const (
+	defaultBaseURL = "https://gitlab.com/api/v4/"
+	tokenHeader    = "PRIVATE-TOKEN"
+)
+
+// draftReleasedAt is the release date given to draft releases. GitLab has no draft releases,
+// so drafts are created as upcoming releases and get the current date when published.
+var draftReleasedAt = time.Date(2999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Client is a minimal client for the GitLab REST API v4.
+type Client struct {
+	BaseURL *url.URL
+
+	accessToken string
+	client      *http.Client
+}
+
+// ErrorResponse reports an error caused by an API request.
+type ErrorResponse struct {
+	Response *http.Response
+	Message  string
+}
+
+// Error returns the description of the error response.
+func (r *ErrorResponse) Error() string {
+	return fmt.Sprintf("%v %v: %d %s",
+		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Message)
+}
+
+// RepositoryClient for Gitlab API.
+type RepositoryClient struct {
+	organization string
+	repo         string
+	client       *Client
+	time         ergo.Time
+}
+
+type release struct {
+	TagName         string     `json:"tag_name"`
+	Description     string     `json:"description"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"`
+	UpcomingRelease bool       `json:"upcoming_release"`
+	Links           struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+type mergeRequest struct {
+	IID    int      `json:"iid"`
+	Title  string   `json:"title"`
+	State  string   `json:"state"`
+	WebURL string   `json:"web_url"`
+	Labels []string `json:"labels"`
+	Author struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+type commit struct {
+	ID         string    `json:"id"`
+	AuthorName string    `json:"author_name"`
+	AuthoredAt time.Time `json:"authored_date"`
+	Message    string    `json:"message"`
+}
+
+type comparison struct {
+	Commits []commit `json:"commits"`
+}
+
+type branch struct {
+	Name   string `json:"name"`
+	Commit commit `json:"commit"`
+}
+
+type commitStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	TargetURL string `json:"target_url"`
+}
+
+type tag struct {
+	Name   string `json:"name"`
+	Commit commit `json:"commit"`
+}
+
+// NewGitlabClient set up a gitlab client. An empty baseURL targets gitlab.com.
+func NewGitlabClient(baseURL, accessToken string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing gitlab base url: %w", err)
+	}
+
+	return &Client{BaseURL: u, accessToken: accessToken, client: http.DefaultClient}, nil
+}
+
+// NewRepositoryClient instantiate a RepositoryClient.
+func NewRepositoryClient(organization, repo string, client *Client) *RepositoryClient {
+	return &RepositoryClient{
+		organization: organization,
+		repo:         repo,
+		client:       client,
+		time:         ergoTime.Time{},
+	}
+}
+
+// SetTime sets the time used to wait between the polls of the checks and to date the published
+// releases.
+func (gc *RepositoryClient) SetTime(t ergo.Time) {
+	gc.time = t
+}
+
+// CreateDraftRelease creates a draft release. The release is created as an upcoming release.
+func (gc *RepositoryClient) CreateDraftRelease(ctx context.Context, name, tagName, releaseBody, targetBranch string) error {
+	payload := map[string]interface{}{
+		"name":        name,
+		"tag_name":    tagName,
+		"description": releaseBody,
+		"ref":         targetBranch,
+		"released_at": draftReleasedAt,
+	}
+
+	return gc.client.do(ctx, http.MethodPost, gc.projectPath("releases"), payload, nil)
+}
+
+// LastRelease fetches the latest published release for a repository. The upcoming releases, which
+// include the drafts, are skipped.
+func (gc *RepositoryClient) LastRelease(ctx context.Context) (*ergo.Release, error) {
+	published := func(r *release) bool { return !r.UpcomingRelease }
+	releases, err := gc.listReleases(ctx, "order_by=released_at&sort=desc", published)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 || !published(&releases[len(releases)-1]) {
+		return nil, errors.New("latest release not found")
+	}
+
+	return toErgoRelease(&releases[len(releases)-1]), nil
+}
+
+// GetReleaseByTag fetches the release of the tag. It returns nil if the tag has no release.
+func (gc *RepositoryClient) GetReleaseByTag(ctx context.Context, tagName string) (*ergo.Release, error) {
+	var gitlabRelease release
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("releases/"+url.PathEscape(tagName)), nil, &gitlabRelease)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting release of tag %s: %w", tagName, err)
+	}
+
+	return toErgoRelease(&gitlabRelease), nil
+}
+
+// ListReleases returns the releases of the project, latest first.
+func (gc *RepositoryClient) ListReleases(ctx context.Context) ([]*ergo.Release, error) {
+	gitlabReleases, err := gc.listReleases(ctx, "order_by=released_at&sort=desc", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error listing releases: %w", err)
+	}
+
+	releases := make([]*ergo.Release, 0, len(gitlabReleases))
+	for i := range gitlabReleases {
+		releases = append(releases, toErgoRelease(&gitlabReleases[i]))
+	}
+	return releases, nil
+}
+
+// listReleases returns the releases of the project in the order of the query, page by page. If
+// found is not nil, it stops at the first release found, which is the last one returned.
+func (gc *RepositoryClient) listReleases(ctx context.Context, query string, found func(*release) bool) ([]release, error) {
+	var releases []release
+	for page := "1"; page != ""; {
+		path := "releases?per_page=100&page=" + page
+		if query != "" {
+			path += "&" + query
+		}
+		var pageReleases []release
+		header, err := gc.client.send(ctx, http.MethodGet, gc.projectPath(path), nil, &pageReleases)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pageReleases {
+			releases = append(releases, pageReleases[i])
+			if found != nil && found(&pageReleases[i]) {
+				return releases, nil
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return releases, nil
+}
+
+// EditRelease allows to edit a repository release.
+func (gc *RepositoryClient) EditRelease(ctx context.Context, rel *ergo.Release) (*ergo.Release, error) {
+	if rel == nil {
+		return nil, errors.New("nothing to release: input release is nil")
+	}
+	payload := map[string]interface{}{
+		"description": rel.Body,
+	}
+
+	var gitlabRelease release
+	err := gc.client.do(ctx, http.MethodPut, gc.projectPath("releases/"+url.PathEscape(rel.TagName)), payload, &gitlabRelease)
+	if err != nil {
+		return nil, err
+	}
+
+	rel.Body = gitlabRelease.Description
+
+	return rel, nil
+}
+
+// PublishRelease sets the release date of an upcoming release to now.
+func (gc *RepositoryClient) PublishRelease(ctx context.Context, releaseID int64) error {
+	releases, err := gc.listReleases(ctx, "", nil)
+	if err != nil {
+		return fmt.Errorf("listing releases: %w", err)
+	}
+
+	for _, r := range releases {
+		if releaseID != releaseIDFromTag(r.TagName) {
+			continue
+		}
+		payload := map[string]interface{}{
+			"released_at": gc.time.Now().UTC(),
+		}
+		err = gc.client.do(ctx, http.MethodPut, gc.projectPath("releases/"+url.PathEscape(r.TagName)), payload, nil)
+		if err != nil {
+			return fmt.Errorf("editing release date of release (ID=%d): %w", releaseID, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("release (ID=%d) not found", releaseID)
+}
+
+// GetRef branch reference object given the branch name.
+func (gc *RepositoryClient) GetRef(ctx context.Context, branchName string) (*ergo.Reference, error) {
+	var b branch
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("repository/branches/"+url.PathEscape(branchName)), nil, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ergo.Reference{SHA: b.Commit.ID, Ref: "refs/heads/" + b.Name}, nil
+}
+
+// CreateTag given the version name.
+func (gc *RepositoryClient) CreateTag(ctx context.Context, versionName, sha, m string) (*ergo.Tag, error) {
+	payload := map[string]interface{}{
+		"tag_name": versionName,
+		"ref":      sha,
+		"message":  m,
+	}
+
+	var t tag
+	err := gc.client.do(ctx, http.MethodPost, gc.projectPath("repository/tags"), payload, &t)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error on tag creation: %v", err)
+	}
+
+	return &ergo.Tag{Name: t.Name}, nil
+}
+
+// CompareBranch compare the base branch with the given one.
+func (gc *RepositoryClient) CompareBranch(ctx context.Context, baseBranch, branchName string) (*ergo.StatusReport, error) {
+	commitsAhead, err := gc.commitsDiff(ctx, baseBranch, branchName)
+	if err != nil {
+		return nil, err
+	}
+
+	commitsBehind, err := gc.commitsDiff(ctx, branchName, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ergo.StatusReport{
+		Branch:     branchName,
+		BaseBranch: baseBranch,
+		Ahead:      commitsAhead,
+		Behind:     commitsBehind,
+		AheadBy:    len(commitsAhead),
+		BehindBy:   len(commitsBehind),
+	}, nil
+}
+
+// commitsDiff finds the differences in commits between two branches.
+func (gc *RepositoryClient) commitsDiff(ctx context.Context, from, to string) ([]*ergo.Commit, error) {
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+
+	var cmp comparison
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("repository/compare?"+query.Encode()), nil, &cmp)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*ergo.Commit
+	for _, c := range cmp.Commits {
+		commits = append(commits, &ergo.Commit{SHA: c.ID, Author: c.AuthorName, Date: c.AuthoredAt, Message: c.Message})
+	}
+
+	return commits, nil
+}
+
+// DiffCommits is responsible to find the diff-commits and return a StatusReport for each of
+// given releaseBranches.
+func (gc *RepositoryClient) DiffCommits(ctx context.Context, releaseBranches []string, baseBranch string) ([]*ergo.StatusReport, error) {
+	var statusReports []*ergo.StatusReport
+	for _, branchName := range releaseBranches {
+		statusReport, err := gc.CompareBranch(ctx, baseBranch, branchName)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing base branch %s %s:%s", baseBranch, branchName, err)
+		}
+		statusReports = append(statusReports, statusReport)
+	}
+	return statusReports, nil
+}
+
+// UpdateBranchFromTag is responsible to update a branch from tag. The GitLab API cannot move a
+// branch, so the branch is deleted and recreated on the tag's commit. Without force the branch
+// must not contain commits missing from the tag. Protected branches have to allow deletion.
+func (gc *RepositoryClient) UpdateBranchFromTag(ctx context.Context, tagName, toBranch string, force bool) error {
+	ref, err := gc.GetRefFromTag(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	if ref == nil {
+		return fmt.Errorf("error on update branch from tag: tag %s not found", tagName)
+	}
+
+	if err = gc.updateBranch(ctx, ref.SHA, toBranch, force); err != nil {
+		return fmt.Errorf("error on update branch from tag: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateBranchToSHA recreates the branch on the commit. Without force the branch must not have
+// commits which the commit does not contain.
+func (gc *RepositoryClient) UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error {
+	if err := gc.updateBranch(ctx, sha, toBranch, force); err != nil {
+		return fmt.Errorf("error on update branch to %s: %v", sha, err)
+	}
+
+	return nil
+}
+
+// updateBranch deletes the branch and creates it again on the commit. Since the API can't move a
+// branch in one request, the branch is recreated on its previous commit if the creation fails.
+func (gc *RepositoryClient) updateBranch(ctx context.Context, sha, toBranch string, force bool) error {
+	if !force {
+		diverged, err := gc.commitsDiff(ctx, sha, toBranch)
+		if err != nil {
+			return err
+		}
+		if len(diverged) > 0 {
+			return fmt.Errorf("branch %s has diverged from %s", toBranch, sha)
+		}
+	}
+
+	previous, err := gc.GetRef(ctx, toBranch)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	err = gc.client.do(ctx, http.MethodDelete, gc.projectPath("repository/branches/"+url.PathEscape(toBranch)), nil, nil)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	if err = gc.createBranch(ctx, toBranch, sha); err == nil || previous == nil {
+		return err
+	}
+	if errRestore := gc.createBranch(ctx, toBranch, previous.SHA); errRestore != nil {
+		return fmt.Errorf("%v, and restoring %s to %s failed: %v", err, toBranch, previous.SHA, errRestore)
+	}
+	return fmt.Errorf("%v, %s was restored to %s", err, toBranch, previous.SHA)
+}
+
+// createBranch creates the branch on the commit.
+func (gc *RepositoryClient) createBranch(ctx context.Context, branch, sha string) error {
+	payload := map[string]interface{}{
+		"branch": branch,
+		"ref":    sha,
+	}
+	return gc.client.do(ctx, http.MethodPost, gc.projectPath("repository/branches"), payload, nil)
+}
+
+// GetRefFromTag get reference from tag.
+func (gc *RepositoryClient) GetRefFromTag(ctx context.Context, tagName string) (*ergo.Reference, error) {
+	var t tag
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("repository/tags/"+url.PathEscape(tagName)), nil, &t)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting tag reference %v", err)
+	}
+
+	return &ergo.Reference{SHA: t.Commit.ID, Ref: "refs/tags/" + t.Name}, nil
+}
+
+// GetRepoName return the repository name.
+func (gc *RepositoryClient) GetRepoName() string {
+	return gc.organization + "/" + gc.repo
+}
+
+// ListPullRequestsWithCommit returns the merged merge requests associated with the commit.
+func (gc *RepositoryClient) ListPullRequestsWithCommit(ctx context.Context, sha string) ([]*ergo.PullRequest, error) {
+	var mergeRequests []mergeRequest
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("repository/commits/"+sha+"/merge_requests"), nil, &mergeRequests)
+	if err != nil {
+		return nil, fmt.Errorf("error listing merge requests of commit %s: %w", sha, err)
+	}
+
+	var pulls []*ergo.PullRequest
+	for _, mr := range mergeRequests {
+		if mr.State != "merged" {
+			continue
+		}
+		pulls = append(pulls, &ergo.PullRequest{
+			Number: mr.IID,
+			Title:  mr.Title,
+			Author: mr.Author.Username,
+			URL:    mr.WebURL,
+			Labels: mr.Labels,
+		})
+	}
+	return pulls, nil
+}
+
+// WaitForChecks polls the commit statuses of the pipelines until all of them have completed or
+// one of them has failed. The last report is returned when the context ends.
+func (gc *RepositoryClient) WaitForChecks(ctx context.Context, sha string, pollInterval time.Duration) (*ergo.ChecksReport, error) {
+	return ergo.PollChecks(ctx, gc.time, pollInterval, func(ctx context.Context) (*ergo.ChecksReport, error) {
+		return gc.checks(ctx, sha)
+	})
+}
+
+// checks fetches the commit statuses of the commit.
+func (gc *RepositoryClient) checks(ctx context.Context, sha string) (*ergo.ChecksReport, error) {
+	var statuses []commitStatus
+	err := gc.client.do(ctx, http.MethodGet, gc.projectPath("repository/commits/"+sha+"/statuses?all=false&per_page=100"), nil, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf("error getting commit statuses: %w", err)
+	}
+
+	checks := make([]*ergo.Check, 0, len(statuses))
+	for _, status := range statuses {
+		state := ergo.CheckStateFailure
+		switch status.Status {
+		case "success", "skipped", "manual":
+			state = ergo.CheckStateSuccess
+		case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+			state = ergo.CheckStatePending
+		}
+		checks = append(checks, &ergo.Check{Name: status.Name, State: state, URL: status.TargetURL})
+	}
+
+	return ergo.NewChecksReport(sha, checks), nil
+}
+
+// projectPath returns the API path of a project resource.
+func (gc *RepositoryClient) projectPath(resource string) string {
+	return "projects/" + url.PathEscape(gc.GetRepoName()) + "/" + resource
+}
+
+// do sends an API request and decodes the JSON response into v, if v is not nil.
+func (c *Client) do(ctx context.Context, method, path string, payload, v interface{}) error {
+	_, err := c.send(ctx, method, path, payload, v)
+	return err
+}
+
+// send sends an API request, decodes the JSON response into v, if v is not nil, and returns the
+// headers of the response, which hold the pagination.
+func (c *Client) send(ctx context.Context, method, path string, payload, v interface{}) (http.Header, error) {
+	u, err := c.BaseURL.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		buf := &bytes.Buffer{}
+		if err = json.NewEncoder(buf).Encode(payload); err != nil {
+			return nil, err
+		}
+		body = buf
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(tokenHeader, c.accessToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		return nil, &ErrorResponse{Response: resp, Message: strings.TrimSpace(string(data))}
+	}
+
+	if v == nil {
+		return resp.Header, nil
+	}
+
+	return resp.Header, json.NewDecoder(resp.Body).Decode(v)
+}
+
+// isNotFound checks if the error is an API response with status not found.
+func isNotFound(err error) bool {
+	var errResponse *ErrorResponse
+	return errors.As(err, &errResponse) && errResponse.Response.StatusCode == http.StatusNotFound
+}
+
+// toErgoRelease converts a gitlab release to the ergo release.
+func toErgoRelease(r *release) *ergo.Release {
+	return &ergo.Release{
+		ID:         releaseIDFromTag(r.TagName),
+		Body:       r.Description,
+		TagName:    r.TagName,
+		ReleaseURL: r.Links.Self,
+		Draft:      r.UpcomingRelease,
+	}
+}
+
+// releaseIDFromTag derives a stable numeric release ID from the tag name, since GitLab
+// identifies releases by their tag.
+func releaseIDFromTag(tagName string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(tagName))
+	return int64(h.Sum64() & (1<<63 - 1))
+}
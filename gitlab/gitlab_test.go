@@ -0,0 +1,611 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func setup() (client *Client, mux *http.ServeMux, teardown func()) {
+	const baseURLPath = "/api/v4"
+
+	mux = http.NewServeMux()
+
+	apiHandler := http.NewServeMux()
+	apiHandler.Handle(baseURLPath+"/", http.StripPrefix(baseURLPath, mux))
+
+	server := httptest.NewServer(apiHandler)
+	client, _ = NewGitlabClient(server.URL+baseURLPath, "token")
+
+	return client, mux, server.Close
+}
+
+func testMethod(t *testing.T, r *http.Request, want string) {
+	if want != r.Method {
+		t.Errorf("Request method = %v, want %v", r.Method, want)
+	}
+}
+
+func TestNewGitlabClient(t *testing.T) {
+	client, err := NewGitlabClient("", "access_token")
+	if err != nil {
+		t.Fatalf("NewGitlabClient should not return the error: %v", err)
+	}
+	if got, want := client.BaseURL.String(), defaultBaseURL; got != want {
+		t.Errorf("got = %v; want %v", got, want)
+	}
+}
+
+func TestNewRepositoryClientShouldReturnANewObject(t *testing.T) {
+	client, _, teardown := setup()
+	defer teardown()
+
+	got := NewRepositoryClient("o", "r", client)
+
+	if got == nil {
+		t.Fatal("NewRepositoryClient should return a new RepositoryClient object.")
+	}
+}
+
+func TestCreateDraftReleaseShouldCreateAnUpcomingRelease(t *testing.T) {
+	ctx := context.Background()
+	client, mux, teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		if got := r.Header.Get(tokenHeader); got != "token" {
+			t.Errorf("token header = %q, want %q", got, "token")
+		}
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload["tag_name"] != "1.0.0" || payload["ref"] != "master" || payload["released_at"] == "" {
+			t.Errorf("unexpected payload %v", payload)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	err := repClient.CreateDraftRelease(ctx, "1.0.0", "1.0.0", "body", "master")
+	if err != nil {
+		t.Fatalf("CreateDraftRelease should not return the error: %v", err)
+	}
+}
+
+func TestLastReleaseShouldReturnTheLastRelease(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	want := &ergo.Release{
+		ID:         releaseIDFromTag("1.0.0"),
+		Body:       "release_body",
+		TagName:    "1.0.0",
+		ReleaseURL: "url",
+	}
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprintf(w, `[{ "tag_name": "%s", "description": "%s", "_links": { "self": "%s" } }]`,
+			want.TagName, want.Body, want.ReleaseURL)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.LastRelease(ctx)
+	if err != nil {
+		t.Fatalf("LastRelease should not return the error: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("got = %v; want %v", *got, *want)
+	}
+}
+
+func TestLastReleaseShouldSkipTheDrafts(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	pages := 0
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			fmt.Fprint(w, `[{ "tag_name": "1.2.0", "upcoming_release": true }]`)
+		case "2":
+			w.Header().Set("X-Next-Page", "3")
+			fmt.Fprint(w, `[{ "tag_name": "1.1.0", "upcoming_release": true }, { "tag_name": "1.0.0" }]`)
+		default:
+			t.Error("LastRelease should stop at the first published release")
+			fmt.Fprint(w, `[{ "tag_name": "0.9.0" }]`)
+		}
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).LastRelease(context.Background())
+	if err != nil {
+		t.Fatalf("LastRelease should not return the error: %v", err)
+	}
+	if got.TagName != "1.0.0" || got.Draft || pages != 2 {
+		t.Errorf("expected the published release 1.0.0 in 2 pages, got %+v in %d", got, pages)
+	}
+}
+
+func TestLastReleaseShouldReturnErrorWhenThereAreOnlyDrafts(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{ "tag_name": "1.2.0", "upcoming_release": true }]`)
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).LastRelease(context.Background())
+	if err == nil || got != nil {
+		t.Errorf("expected the latest release not found error, got %v and %v", got, err)
+	}
+}
+
+func TestGetReleaseByTagShouldReturnTheReleaseOfTheTag(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{ "tag_name": "1.2.0", "description": "release_body", "_links": { "self": "url" } }`)
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).GetReleaseByTag(context.Background(), "1.2.0")
+	if err != nil {
+		t.Fatalf("GetReleaseByTag should not return the error: %v", err)
+	}
+	if got == nil || got.TagName != "1.2.0" || got.Body != "release_body" || got.ID != releaseIDFromTag("1.2.0") {
+		t.Errorf("unexpected release %v", got)
+	}
+}
+
+func TestGetReleaseByTagShouldReturnNilForStatusNotFound(t *testing.T) {
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases/1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	got, err := NewRepositoryClient("o", "r", client).GetReleaseByTag(context.Background(), "1.2.0")
+	if err != nil || got != nil {
+		t.Errorf("expected no release and no error, got %v, %v", got, err)
+	}
+}
+
+func TestLastReleaseShouldReturnErrorWhenThereAreNoReleases(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.LastRelease(ctx)
+	if err == nil {
+		t.Error("Should return error when no release exists")
+	}
+	if got != nil {
+		t.Error("Release should be nil")
+	}
+}
+
+func TestLastReleaseShouldReturnErrorForServerError(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.LastRelease(ctx)
+	if err == nil {
+		t.Error("Should return error for internal server error")
+	}
+	if got != nil {
+		t.Error("Release should be nil")
+	}
+}
+
+func TestEditReleaseShouldEditTheRelease(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		fmt.Fprint(w, `{ "tag_name": "1.0.0", "description": "new body" }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.EditRelease(ctx, &ergo.Release{TagName: "1.0.0", Body: "new body"})
+	if err != nil {
+		t.Fatalf("EditRelease should not return the error: %v", err)
+	}
+	if got.Body != "new body" {
+		t.Errorf("got = %v; want %v", got.Body, "new body")
+	}
+}
+
+func TestEditReleaseShouldReturnErrorForNilInputRelease(t *testing.T) {
+	client, _, tearDown := setup()
+	defer tearDown()
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	if _, err := repClient.EditRelease(context.Background(), nil); err == nil {
+		t.Error("EditRelease should return error for nil release")
+	}
+}
+
+func TestPublishReleaseShouldSetTheReleaseDate(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	now := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+	var releasedAt time.Time
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{ "tag_name": "0.9.0" }, { "tag_name": "1.0.0", "upcoming_release": true }]`)
+	})
+	mux.HandleFunc("/projects/o/r/releases/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		var payload struct {
+			ReleasedAt time.Time `json:"released_at"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		releasedAt = payload.ReleasedAt
+		fmt.Fprint(w, `{}`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+	repClient.SetTime(mock.NewMockedTime(now))
+
+	if err := repClient.PublishRelease(ctx, releaseIDFromTag("1.0.0")); err != nil {
+		t.Fatalf("PublishRelease should not return the error: %v", err)
+	}
+	if !releasedAt.Equal(now) {
+		t.Errorf("expected the release to be published at %v, got %v", now, releasedAt)
+	}
+}
+
+func TestListReleasesShouldReturnAllPages(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{ "tag_name": "1.0.0" }]`)
+			return
+		}
+		w.Header().Set("X-Next-Page", "2")
+		fmt.Fprint(w, `[{ "tag_name": "1.2.0", "upcoming_release": true }, { "tag_name": "1.1.0" }]`)
+	})
+	published := false
+	mux.HandleFunc("/projects/o/r/releases/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		published = true
+		fmt.Fprint(w, `{}`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.ListReleases(ctx)
+	if err != nil {
+		t.Fatalf("ListReleases should not return the error: %v", err)
+	}
+	if len(got) != 3 || !got[0].Draft || got[2].TagName != "1.0.0" {
+		t.Errorf("unexpected releases %+v", got)
+	}
+	if err = repClient.PublishRelease(ctx, releaseIDFromTag("1.0.0")); err != nil || !published {
+		t.Errorf("expected the release of the second page to be published, got %v", err)
+	}
+}
+
+func TestPublishReleaseShouldReturnErrorForUnknownRelease(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{ "tag_name": "0.9.0" }]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	if err := repClient.PublishRelease(ctx, releaseIDFromTag("1.0.0")); err == nil {
+		t.Fatal("PublishRelease should return error for unknown release")
+	}
+}
+
+func TestCreateTagShouldCreateANewTag(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/tags", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		fmt.Fprint(w, `{ "name": "1.0.0", "commit": { "id": "sha" } }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.CreateTag(ctx, "1.0.0", "sha", "")
+	if err != nil {
+		t.Fatalf("CreateTag should not return the error: %v", err)
+	}
+	if got.Name != "1.0.0" {
+		t.Errorf("got = %v; want %v", got.Name, "1.0.0")
+	}
+}
+
+func TestCompareBranchShouldCompareBranches(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/compare", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("from") == "base" {
+			fmt.Fprint(w, `{ "commits": [{ "id": "a", "message": "ahead" }] }`)
+			return
+		}
+		fmt.Fprint(w, `{ "commits": [{ "id": "b", "message": "behind" }, { "id": "c", "message": "behind" }] }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.CompareBranch(ctx, "base", "branch")
+	if err != nil {
+		t.Fatalf("CompareBranch should not return the error: %v", err)
+	}
+	if got.BaseBranch != "base" || got.Branch != "branch" {
+		t.Fatalf("unexpected branches %v", got)
+	}
+	if len(got.Ahead) != 1 {
+		t.Errorf("StatusReport.Ahead has %d elements, want 1", len(got.Ahead))
+	}
+	if len(got.Behind) != 2 {
+		t.Errorf("StatusReport.Behind has %d elements, want 2", len(got.Behind))
+	}
+}
+
+func TestDiffCommitsShouldReturnErrorForInvalidPayload(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/compare", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "invalid_payload")
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	diffCommits, err := repClient.DiffCommits(ctx, []string{"base"}, "branch")
+	if err == nil {
+		t.Fatal("DiffCommits should return error for invalid payload")
+	}
+	if diffCommits != nil {
+		t.Error("DiffCommits should return nil on error")
+	}
+}
+
+func TestUpdateBranchFromTagShouldRecreateTheBranch(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	var calls []string
+	mux.HandleFunc("/projects/o/r/repository/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "name": "1.0.0", "commit": { "id": "sha" } }`)
+	})
+	mux.HandleFunc("/projects/o/r/repository/compare", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "commits": [] }`)
+	})
+	mux.HandleFunc("/projects/o/r/repository/branches/release", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{ "name": "release", "commit": { "id": "old" } }`)
+			return
+		}
+		testMethod(t, r, http.MethodDelete)
+		calls = append(calls, "delete")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/projects/o/r/repository/branches", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPost)
+		calls = append(calls, "create")
+		fmt.Fprint(w, `{ "name": "release", "commit": { "id": "sha" } }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	if err := repClient.UpdateBranchFromTag(ctx, "1.0.0", "release", false); err != nil {
+		t.Fatalf("Should not return the error: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "delete" || calls[1] != "create" {
+		t.Errorf("got calls %v; want [delete create]", calls)
+	}
+}
+
+func TestUpdateBranchToSHAShouldRestoreTheBranchWhenItCannotBeRecreated(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	var created []string
+	mux.HandleFunc("/projects/o/r/repository/branches/release", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{ "name": "release", "commit": { "id": "old" } }`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/projects/o/r/repository/branches", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, payload["ref"])
+		if payload["ref"] == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{ "message": "Invalid reference name" }`)
+			return
+		}
+		fmt.Fprint(w, `{ "name": "release", "commit": { "id": "old" } }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	err := repClient.UpdateBranchToSHA(ctx, "missing", "release", true)
+	if err == nil || !strings.Contains(err.Error(), "restored to old") {
+		t.Fatalf("expected the error of the restored update, got %v", err)
+	}
+	if len(created) != 2 || created[1] != "old" {
+		t.Errorf("expected the branch to be recreated on its previous commit, got %v", created)
+	}
+}
+
+func TestUpdateBranchFromTagShouldReturnErrorWhenBranchHasDiverged(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "name": "1.0.0", "commit": { "id": "sha" } }`)
+	})
+	mux.HandleFunc("/projects/o/r/repository/compare", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "commits": [{ "id": "a", "message": "hotfix" }] }`)
+	})
+	mux.HandleFunc("/projects/o/r/repository/branches/release", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("diverged branch should not be deleted")
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	if err := repClient.UpdateBranchFromTag(ctx, "1.0.0", "release", false); err == nil {
+		t.Fatal("UpdateBranchFromTag should return error for diverged branch")
+	}
+}
+
+func TestGetRefFromTagShouldReturnNilForStatusNotFound(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/tags/1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.GetRefFromTag(ctx, "1.0.0")
+	if err != nil {
+		t.Fatalf("Should not return error for status not found, error: %v", err)
+	}
+	if got != nil {
+		t.Error("Should return nil on status not found")
+	}
+}
+
+func TestGetRefShouldReturnTheReference(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	want := &ergo.Reference{SHA: "sha", Ref: "refs/heads/test"}
+
+	mux.HandleFunc("/projects/o/r/repository/branches/test", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "name": "test", "commit": { "id": "sha" } }`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.GetRef(ctx, "test")
+	if err != nil {
+		t.Fatalf("Should not return the error: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("got = %v; want %v", *got, *want)
+	}
+}
+
+func TestGetRepoNameShouldReturnTheFullName(t *testing.T) {
+	repClient := NewRepositoryClient("o", "r", nil)
+
+	if got := repClient.GetRepoName(); got != "o/r" {
+		t.Errorf("got = %v; want %v", got, "o/r")
+	}
+}
+
+func TestWaitForChecksShouldReturnTheCombinedState(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	polls := 0
+	mux.HandleFunc("/projects/o/r/repository/commits/sha/statuses", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 2 {
+			fmt.Fprint(w, `[{ "name": "build", "status": "success" }, { "name": "test", "status": "running" }]`)
+			return
+		}
+		fmt.Fprint(w, `[{ "name": "build", "status": "success" }, { "name": "test", "status": "failed" }]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.WaitForChecks(ctx, "sha", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForChecks should not return the error: %v", err)
+	}
+	if got.State != ergo.CheckStateFailure || polls != 2 {
+		t.Errorf("unexpected report %+v after %d polls", got, polls)
+	}
+}
+
+func TestListPullRequestsWithCommitShouldReturnTheMergedMergeRequests(t *testing.T) {
+	ctx := context.Background()
+	client, mux, tearDown := setup()
+	defer tearDown()
+
+	mux.HandleFunc("/projects/o/r/repository/commits/sha/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `[
+			{ "iid": 1, "title": "Add login", "state": "merged", "author": { "username": "alice" }, "labels": ["feature"] },
+			{ "iid": 2, "title": "Open", "state": "opened", "author": { "username": "bob" } }
+		]`)
+	})
+
+	repClient := NewRepositoryClient("o", "r", client)
+
+	got, err := repClient.ListPullRequestsWithCommit(ctx, "sha")
+	if err != nil {
+		t.Fatalf("ListPullRequestsWithCommit should not return the error: %v", err)
+	}
+	if len(got) != 1 || got[0].Number != 1 || got[0].Author != "alice" || got[0].Labels[0] != "feature" {
+		t.Errorf("unexpected merge requests %v", got)
+	}
+}
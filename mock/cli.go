@@ -11,11 +11,13 @@ import (
 // CLI is a mock implementation.
 type CLI struct {
 	ConfirmationFn func() (bool, error)
+	InputFn        func() (string, error)
 
 	mu                sync.Mutex
 	ConfirmationCalls int
 	PrintTableCalls   []PrintTableVal
 	PrintLines        []string
+	PrintObjectCalls  []interface{}
 }
 
 // PrintTableVal represents the values send to the PrintTable method.
@@ -31,6 +33,13 @@ func (c *CLI) PrintTable(header []string, values [][]string) {
 	c.PrintTableCalls = append(c.PrintTableCalls, PrintTableVal{Header: header, Values: values})
 }
 
+// PrintObject is a mock implementation.
+func (c *CLI) PrintObject(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.PrintObjectCalls = append(c.PrintObjectCalls, v)
+}
+
 // PrintColorizedLine is a mock implementation.
 func (c *CLI) PrintColorizedLine(title, content string, level ergo.MessageLevel) {}
 
@@ -56,5 +65,8 @@ func (c *CLI) Confirmation(actionText, cancellationMessage, successMessage strin
 
 // Input is a mock implementation.
 func (c *CLI) Input() (string, error) {
+	if c.InputFn != nil {
+		return c.InputFn()
+	}
 	return "", nil
 }
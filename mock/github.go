@@ -2,23 +2,29 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/beatlabs/ergo"
 )
 
 // RepositoryClient is a mock implementation.
 type RepositoryClient struct {
-	CreateDraftReleaseFn  func() error
-	LastReleaseFn         func() (*ergo.Release, error)
-	EditReleaseFn         func() (*ergo.Release, error)
-	PublishReleaseFn      func(ctx context.Context, releaseID int64) error
-	CompareBranchFn       func() (*ergo.StatusReport, error)
-	DiffCommitsFn         func() ([]*ergo.StatusReport, error)
-	CreateTagFn           func() (*ergo.Tag, error)
-	UpdateBranchFromTagFn func() error
-	GetRefFn              func() (*ergo.Reference, error)
-	GetRefFromTagFn       func() (*ergo.Reference, error)
-	GetRepoNameFn         func() string
+	CreateDraftReleaseFn         func() error
+	LastReleaseFn                func() (*ergo.Release, error)
+	GetReleaseByTagFn            func(tagName string) (*ergo.Release, error)
+	ListReleasesFn               func() ([]*ergo.Release, error)
+	EditReleaseFn                func() (*ergo.Release, error)
+	PublishReleaseFn             func(ctx context.Context, releaseID int64) error
+	CompareBranchFn              func() (*ergo.StatusReport, error)
+	DiffCommitsFn                func() ([]*ergo.StatusReport, error)
+	CreateTagFn                  func() (*ergo.Tag, error)
+	UpdateBranchFromTagFn        func() error
+	UpdateBranchToSHAFn          func() error
+	GetRefFn                     func(branch string) (*ergo.Reference, error)
+	GetRefFromTagFn              func(tag string) (*ergo.Reference, error)
+	GetRepoNameFn                func() string
+	WaitForChecksFn              func() (*ergo.ChecksReport, error)
+	ListPullRequestsWithCommitFn func(sha string) ([]*ergo.PullRequest, error)
 }
 
 // CreateDraftRelease is a mock implementation.
@@ -37,6 +43,22 @@ func (r *RepositoryClient) LastRelease(ctx context.Context) (*ergo.Release, erro
 	return nil, nil
 }
 
+// GetReleaseByTag is a mock implementation.
+func (r *RepositoryClient) GetReleaseByTag(ctx context.Context, tagName string) (*ergo.Release, error) {
+	if r.GetReleaseByTagFn != nil {
+		return r.GetReleaseByTagFn(tagName)
+	}
+	return nil, nil
+}
+
+// ListReleases is a mock implementation.
+func (r *RepositoryClient) ListReleases(ctx context.Context) ([]*ergo.Release, error) {
+	if r.ListReleasesFn != nil {
+		return r.ListReleasesFn()
+	}
+	return nil, nil
+}
+
 // EditRelease is a mock implementation.
 func (r *RepositoryClient) EditRelease(ctx context.Context, release *ergo.Release) (*ergo.Release, error) {
 	if r.EditReleaseFn != nil {
@@ -85,10 +107,18 @@ func (r *RepositoryClient) UpdateBranchFromTag(ctx context.Context, tag, toBranc
 	return nil
 }
 
+// UpdateBranchToSHA is a mock implementation.
+func (r *RepositoryClient) UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error {
+	if r.UpdateBranchToSHAFn != nil {
+		return r.UpdateBranchToSHAFn()
+	}
+	return nil
+}
+
 // GetRef is a mock implementation.
 func (r *RepositoryClient) GetRef(ctx context.Context, branch string) (*ergo.Reference, error) {
 	if r.GetRefFn != nil {
-		return r.GetRefFn()
+		return r.GetRefFn(branch)
 	}
 	return nil, nil
 }
@@ -96,7 +126,7 @@ func (r *RepositoryClient) GetRef(ctx context.Context, branch string) (*ergo.Ref
 // GetRefFromTag is a mock implementation.
 func (r *RepositoryClient) GetRefFromTag(ctx context.Context, tag string) (*ergo.Reference, error) {
 	if r.GetRefFromTagFn != nil {
-		return r.GetRefFromTagFn()
+		return r.GetRefFromTagFn(tag)
 	}
 	return nil, nil
 }
@@ -108,3 +138,19 @@ func (r *RepositoryClient) GetRepoName() string {
 	}
 	return ""
 }
+
+// WaitForChecks is a mock implementation.
+func (r *RepositoryClient) WaitForChecks(ctx context.Context, sha string, pollInterval time.Duration) (*ergo.ChecksReport, error) {
+	if r.WaitForChecksFn != nil {
+		return r.WaitForChecksFn()
+	}
+	return &ergo.ChecksReport{SHA: sha, State: ergo.CheckStateSuccess}, nil
+}
+
+// ListPullRequestsWithCommit is a mock implementation.
+func (r *RepositoryClient) ListPullRequestsWithCommit(ctx context.Context, sha string) ([]*ergo.PullRequest, error) {
+	if r.ListPullRequestsWithCommitFn != nil {
+		return r.ListPullRequestsWithCommitFn(sha)
+	}
+	return nil, nil
+}
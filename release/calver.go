@@ -0,0 +1,97 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const calVerMicro = "MICRO"
+
+// calVerSeparators matches the separators allowed between the parts of a calendar version format.
+var calVerSeparators = regexp.MustCompile(`[.\-_]`)
+
+// calVerTokens maps the calendar version format tokens to their value for a given date and year,
+// which is the ISO year of the week in formats with a week.
+var calVerTokens = map[string]func(t time.Time, year int) string{
+	"YYYY": func(t time.Time, year int) string { return strconv.Itoa(year) },
+	"YY":   func(t time.Time, year int) string { return strconv.Itoa(year - 2000) },
+	"0Y":   func(t time.Time, year int) string { return fmt.Sprintf("%02d", year-2000) },
+	"MM":   func(t time.Time, year int) string { return strconv.Itoa(int(t.Month())) },
+	"0M":   func(t time.Time, year int) string { return fmt.Sprintf("%02d", int(t.Month())) },
+	"WW":   func(t time.Time, year int) string { _, w := t.ISOWeek(); return strconv.Itoa(w) },
+	"0W":   func(t time.Time, year int) string { _, w := t.ISOWeek(); return fmt.Sprintf("%02d", w) },
+	"DD":   func(t time.Time, year int) string { return strconv.Itoa(t.Day()) },
+	"0D":   func(t time.Time, year int) string { return fmt.Sprintf("%02d", t.Day()) },
+}
+
+// ValidateCalVerFormat checks that the calendar version format only consists of known tokens
+// and contains a year.
+func ValidateCalVerFormat(format string) error {
+	var hasYear bool
+	for _, part := range calVerSeparators.Split(format, -1) {
+		if _, ok := calVerTokens[part]; !ok && part != calVerMicro {
+			return fmt.Errorf("invalid calendar version format %q: unknown part %q", format, part)
+		}
+		if part == "YYYY" || part == "YY" || part == "0Y" {
+			hasYear = true
+		}
+	}
+	if !hasYear {
+		return fmt.Errorf("invalid calendar version format %q: missing year", format)
+	}
+	return nil
+}
+
+// nextCalVersion finds the next free calendar version for the current date. A MICRO part is
+// used as counter, otherwise a .N counter is appended for releases of the same period.
+func (v Version) nextCalVersion(ctx context.Context, suffix string) (string, error) {
+	if err := ValidateCalVerFormat(v.calVerFormat); err != nil {
+		return "", err
+	}
+
+	now := v.time.Now()
+	separators := calVerSeparators.FindAllString(v.calVerFormat, -1)
+	parts := calVerSeparators.Split(v.calVerFormat, -1)
+	hasMicro := strings.Contains(v.calVerFormat, calVerMicro)
+
+	// The first days of January may be in the last week of the previous year, e.g. 2027-01-01 is
+	// in the week 53 of 2026, and the last days of December in the first week of the next one.
+	year := now.Year()
+	for _, part := range parts {
+		if part == "WW" || part == "0W" {
+			year, _ = now.ISOWeek()
+		}
+	}
+
+	for counter := 0; ; counter++ {
+		var name strings.Builder
+		for i, part := range parts {
+			if i > 0 {
+				name.WriteString(separators[i-1])
+			}
+			if part == calVerMicro {
+				name.WriteString(strconv.Itoa(counter))
+				continue
+			}
+			name.WriteString(calVerTokens[part](now, year))
+		}
+		if !hasMicro && counter > 0 {
+			name.WriteString("." + strconv.Itoa(counter))
+		}
+		if suffix != "" {
+			name.WriteString("-" + suffix)
+		}
+
+		ref, err := v.host.GetRefFromTag(ctx, name.String())
+		if err != nil {
+			return "", err
+		}
+		if ref == nil {
+			return name.String(), nil
+		}
+	}
+}
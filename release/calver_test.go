@@ -0,0 +1,97 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestNextVersionShouldReturnTheCalendarVersion(t *testing.T) {
+	now := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		format       string
+		suffix       string
+		existingTags int
+		want         string
+	}{
+		{name: "full date", format: "YYYY.MM.DD", want: "2026.3.5"},
+		{name: "full date same day", format: "YYYY.MM.DD", existingTags: 2, want: "2026.3.5.2"},
+		{name: "micro", format: "YYYY.0M.MICRO", want: "2026.03.0"},
+		{name: "micro same month", format: "YYYY.0M.MICRO", existingTags: 3, want: "2026.03.3"},
+		{name: "short year and week", format: "YY.WW", want: "26.10"},
+		{name: "week of the previous year", now: time.Date(2027, 1, 1, 10, 0, 0, 0, time.UTC), format: "YYYY.0W", want: "2026.53"},
+		{name: "week of the next year", now: time.Date(2025, 12, 29, 10, 0, 0, 0, time.UTC), format: "YY.WW", want: "26.1"},
+		{name: "year and month without week", now: time.Date(2027, 1, 1, 10, 0, 0, 0, time.UTC), format: "YYYY.0M", want: "2027.01"},
+		{name: "short year before 2010", now: time.Date(2006, 3, 5, 10, 0, 0, 0, time.UTC), format: "YY.MM", want: "6.3"},
+		{name: "padded year before 2010", now: time.Date(2006, 3, 5, 10, 0, 0, 0, time.UTC), format: "0Y.MM", want: "06.3"},
+		{name: "short year after 2099", now: time.Date(2106, 3, 5, 10, 0, 0, 0, time.UTC), format: "YY.MM", want: "106.3"},
+		{name: "padded day with suffix", format: "0Y-0M-0D", suffix: "mx", existingTags: 1, want: "26-03-05.1-mx"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			host := &mock.RepositoryClient{
+				GetRefFn: func(string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "sha"}, nil
+				},
+				GetRefFromTagFn: func(string) (*ergo.Reference, error) {
+					calls++
+					if calls <= test.existingTags {
+						return &ergo.Reference{SHA: "old"}, nil
+					}
+					return nil, nil
+				},
+			}
+			v := NewCalVersion(host, "baseBranch", test.format)
+			if test.now.IsZero() {
+				v.time = mock.NewMockedTime(now)
+			} else {
+				v.time = mock.NewMockedTime(test.now)
+			}
+
+			got, err := v.NextVersion(ctx, "", test.suffix, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != test.want || got.SHA != "sha" {
+				t.Errorf("expected next version to be equal to %v instead of %v", test.want, got.Name)
+			}
+		})
+	}
+}
+
+func TestNextVersionShouldReturnErrorForInvalidCalendarFormat(t *testing.T) {
+	host := &mock.RepositoryClient{
+		GetRefFn: func(string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha"}, nil
+		},
+	}
+
+	for _, format := range []string{"MM.DD", "YYYY.Q", "YYYY..MM"} {
+		if _, err := NewCalVersion(host, "baseBranch", format).NextVersion(ctx, "", "", false, false); err == nil {
+			t.Errorf("expected NextVersion to return error for format %q", format)
+		}
+	}
+}
+
+func TestNextVersionShouldPreferTheInputVersionOverTheCalendarVersion(t *testing.T) {
+	host := &mock.RepositoryClient{
+		GetRefFn: func(string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha"}, nil
+		},
+	}
+
+	got, err := NewCalVersion(host, "baseBranch", "YYYY.MM.DD").NextVersion(ctx, "1.2.3", "", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "1.2.3" {
+		t.Errorf("expected next version to be equal to 1.2.3 instead of %v", got.Name)
+	}
+}
@@ -0,0 +1,185 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/ergo"
+)
+
+// conventionalHeader matches the header of a conventional commit, e.g. "feat(api)!: add login".
+var conventionalHeader = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// breakingChangeFooter matches the breaking change footer of a conventional commit.
+var breakingChangeFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: (.+)$`)
+
+// commitTypeSections are the section titles of the known commit types, in the order of the
+// release body. Other types get a section named after the type, after the known ones.
+var commitTypeSections = []struct{ commitType, title string }{
+	{"feat", "### Features"},
+	{"fix", "### Bug Fixes"},
+	{"perf", "### Performance Improvements"},
+	{"refactor", "### Code Refactoring"},
+	{"revert", "### Reverts"},
+	{"docs", "### Documentation"},
+	{"test", "### Tests"},
+	{"build", "### Build System"},
+	{"ci", "### Continuous Integration"},
+	{"style", "### Styles"},
+	{"chore", "### Chores"},
+}
+
+// ConventionalCommit is a commit whose message follows the Conventional Commits specification.
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+	// BreakingChange is the description of the BREAKING CHANGE footer, if any.
+	BreakingChange string
+	Commit         *ergo.Commit
+}
+
+// CommitSection is a group of conventional commits of the same type.
+type CommitSection struct {
+	Title   string
+	Commits []*ConventionalCommit
+}
+
+// ParseConventionalCommit parses the commit message. The second value is false if the message does
+// not follow the Conventional Commits specification.
+func ParseConventionalCommit(c *ergo.Commit) (*ConventionalCommit, bool) {
+	message := strings.TrimSpace(c.Message)
+	header := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+
+	match := conventionalHeader.FindStringSubmatch(header)
+	if match == nil {
+		return nil, false
+	}
+
+	cc := &ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+		Commit:      c,
+	}
+	if footer := breakingChangeFooter.FindStringSubmatch(message); footer != nil {
+		cc.Breaking = true
+		cc.BreakingChange = strings.TrimSpace(footer[1])
+	}
+	return cc, true
+}
+
+// InferBump infers the semantic version increase of the commits behind the base branch: major
+// for breaking changes, minor for features and patch otherwise.
+func InferBump(statusReports []*ergo.StatusReport) (major, minor bool) {
+	for _, report := range statusReports {
+		for _, c := range report.Behind {
+			cc, ok := ParseConventionalCommit(c)
+			if !ok {
+				continue
+			}
+			if cc.Breaking {
+				return true, false
+			}
+			if cc.Type == "feat" {
+				minor = true
+			}
+		}
+	}
+	return false, minor
+}
+
+// AutoBump infers the semantic version increase from the commits of the base branch which are not
+// deployed to the release branches yet.
+func (v Version) AutoBump(ctx context.Context, releaseBranches []string) (major, minor bool, err error) {
+	diff, err := v.host.DiffCommits(ctx, releaseBranches, v.baseBranch)
+	if err != nil {
+		return false, false, err
+	}
+	major, minor = InferBump(diff)
+	return major, minor, nil
+}
+
+// conventionalSections splits the commits in the breaking changes and the sections by commit type.
+// The commits which are not conventional are listed in a last section.
+func conventionalSections(commits []*ergo.Commit) (breaking []*ConventionalCommit, sections []*CommitSection) {
+	byType := make(map[string][]*ConventionalCommit)
+	var other []*ConventionalCommit
+	for _, c := range commits {
+		cc, ok := ParseConventionalCommit(c)
+		if !ok {
+			other = append(other, &ConventionalCommit{Description: strings.TrimSpace(c.Message), Commit: c})
+			continue
+		}
+		if cc.Breaking {
+			breaking = append(breaking, cc)
+		}
+		byType[cc.Type] = append(byType[cc.Type], cc)
+	}
+
+	for _, s := range commitTypeSections {
+		if ccs, ok := byType[s.commitType]; ok {
+			sections = append(sections, &CommitSection{Title: s.title, Commits: ccs})
+			delete(byType, s.commitType)
+		}
+	}
+	unknownTypes := make([]string, 0, len(byType))
+	for commitType := range byType {
+		unknownTypes = append(unknownTypes, commitType)
+	}
+	sort.Strings(unknownTypes)
+	for _, commitType := range unknownTypes {
+		sections = append(sections, &CommitSection{Title: "### " + commitType, Commits: byType[commitType]})
+	}
+	if len(other) > 0 {
+		sections = append(sections, &CommitSection{Title: otherSection, Commits: other})
+	}
+	return breaking, sections
+}
+
+// conventionalBody renders the default release body of the commits grouped by type.
+func (d *Draft) conventionalBody(model *BodyModel) string {
+	lineSeparator := "\r\n"
+
+	parts := []string{model.Markers()}
+	if model.Prefix != "" {
+		parts = append(parts, model.Prefix)
+	}
+	if len(model.BreakingChanges) > 0 {
+		lines := []string{"### ⚠ BREAKING CHANGES"}
+		for _, cc := range model.BreakingChanges {
+			description := cc.BreakingChange
+			if description == "" {
+				description = cc.Description
+			}
+			lines = append(lines, "- "+scoped(cc.Scope, description))
+		}
+		parts = append(parts, strings.Join(lines, lineSeparator))
+	}
+	for _, section := range model.CommitSections {
+		lines := []string{section.Title}
+		for _, cc := range section.Commits {
+			if cc.Type == "" {
+				lines = append(lines, d.formatMessage(cc.Commit, "- ", "  ", lineSeparator))
+				continue
+			}
+			lines = append(lines, "- "+scoped(cc.Scope, cc.Description))
+		}
+		parts = append(parts, strings.Join(lines, lineSeparator))
+	}
+
+	return strings.Join(parts, strings.Repeat(lineSeparator, 2))
+}
+
+// scoped prefixes the description with the scope, if any.
+func scoped(scope, description string) string {
+	if scope == "" {
+		return description
+	}
+	return fmt.Sprintf("**%s:** %s", scope, description)
+}
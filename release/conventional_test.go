@@ -0,0 +1,157 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message string
+		want    *ConventionalCommit
+	}{
+		{message: "feat: add login", want: &ConventionalCommit{Type: "feat", Description: "add login"}},
+		{message: "fix(api): handle nil\n\nbody", want: &ConventionalCommit{Type: "fix", Scope: "api", Description: "handle nil"}},
+		{message: "refactor(db)!: drop table", want: &ConventionalCommit{Type: "refactor", Scope: "db", Description: "drop table", Breaking: true}},
+		{
+			message: "feat: new config\n\nBREAKING CHANGE: the config file moved",
+			want:    &ConventionalCommit{Type: "feat", Description: "new config", Breaking: true, BreakingChange: "the config file moved"},
+		},
+		{message: "Merge pull request #1 from branch"},
+		{message: "fix typo"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			commit := &ergo.Commit{Message: test.message}
+			got, ok := ParseConventionalCommit(commit)
+			if ok != (test.want != nil) {
+				t.Fatalf("got conventional %t; want %t", ok, test.want != nil)
+			}
+			if !ok {
+				return
+			}
+			test.want.Commit = commit
+			if *got != *test.want {
+				t.Errorf("got %+v; want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInferBump(t *testing.T) {
+	tests := []struct {
+		name      string
+		messages  []string
+		wantMajor bool
+		wantMinor bool
+	}{
+		{name: "patch", messages: []string{"fix: a", "chore: b", "not conventional"}},
+		{name: "minor", messages: []string{"fix: a", "feat: b"}, wantMinor: true},
+		{name: "major", messages: []string{"feat: a", "fix!: b"}, wantMajor: true},
+		{name: "breaking change footer", messages: []string{"fix: a\n\nBREAKING-CHANGE: b"}, wantMajor: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := &ergo.StatusReport{}
+			for _, m := range test.messages {
+				report.Behind = append(report.Behind, &ergo.Commit{Message: m})
+			}
+
+			major, minor := InferBump([]*ergo.StatusReport{report})
+			if major != test.wantMajor || minor != test.wantMinor {
+				t.Errorf("got major %t minor %t; want major %t minor %t", major, minor, test.wantMajor, test.wantMinor)
+			}
+		})
+	}
+}
+
+func TestAutoBumpShouldIncreaseTheNextVersion(t *testing.T) {
+	ctx := context.Background()
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Behind: []*ergo.Commit{{Message: "feat: login"}}}}, nil
+		},
+		GetRefFn: func(string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha"}, nil
+		},
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.2.3"}, nil
+		},
+	}
+	v := NewVersion(host, "master")
+
+	major, minor, err := v.AutoBump(ctx, []string{"release"})
+	if err != nil {
+		t.Fatalf("AutoBump should not return the error: %v", err)
+	}
+	got, err := v.NextVersion(ctx, "", "", major, minor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "1.3.0" {
+		t.Errorf("got version %s; want 1.3.0", got.Name)
+	}
+}
+
+func TestCreateShouldGroupTheCommitsByType(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{
+				Branch: "release-gr",
+				Behind: []*ergo.Commit{
+					{Message: "fix(api): handle nil"},
+					{Message: "feat: add login"},
+					{Message: "feat(config)!: move config\n\nBREAKING CHANGE: the config file moved"},
+					{Message: "wip: experiment"},
+					{Message: "fix typo"},
+				},
+			}}, nil
+		},
+	}
+
+	c := &mock.CLI{}
+	draft := NewDraft(c, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetConventionalCommits()
+
+	if err := draft.Create(context.Background(), "", "2.0.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	want := "release-gr ![](https://img.shields.io/badge/released-No-red.svg)\r\n\r\n" +
+		"### ⚠ BREAKING CHANGES\r\n- **config:** the config file moved\r\n\r\n" +
+		"### Features\r\n- add login\r\n- **config:** move config\r\n\r\n" +
+		"### Bug Fixes\r\n- **api:** handle nil\r\n\r\n" +
+		"### wip\r\n- experiment\r\n\r\n" +
+		"### Other\r\n- fix typo"
+	if len(c.PrintLines) != 1 || c.PrintLines[0] != want {
+		t.Errorf("got body %q; want %q", c.PrintLines, want)
+	}
+}
+
+func TestCreateShouldUseTheDiffTheVersionWasInferredFrom(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			t.Error("the release branches should not be compared again")
+			return nil, nil
+		},
+	}
+	diff := []*ergo.StatusReport{{Branch: "release-gr", Behind: []*ergo.Commit{{Message: "feat: add login"}}}}
+
+	c := &mock.CLI{}
+	draft := NewDraft(c, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetConventionalCommits()
+	draft.SetDiff(diff)
+
+	if err := draft.Create(context.Background(), "", "1.1.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+	want := "release-gr ![](https://img.shields.io/badge/released-No-red.svg)\r\n\r\n### Features\r\n- add login"
+	if len(c.PrintLines) != 1 || c.PrintLines[0] != want {
+		t.Errorf("got body %q; want %q", c.PrintLines, want)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +25,56 @@ type Deploy struct {
 	releaseBranches     []string
 	releaseBodyBranches map[string]string
 	time                ergo.Time
+	statePath           string
+	plan                *DeployPlan
+	checksPollInterval  time.Duration
+	checksTimeout       time.Duration
+	healthChecks        map[string]*HealthCheck
+	httpClient          *http.Client
+	dryRun              bool
+	release             string
+	// waveIntervals are the intervals after each release branch set by the waves, which replace
+	// the release interval, and waveConfirmations the waves confirmed before their first branch.
+	waveIntervals     []time.Duration
+	waveConfirmations map[string]string
+	// startTime is the start time of the first branch set with SetStartTime, if any.
+	startTime time.Time
+	// location is the time zone of the schedule, the board is printed in when no board time
+	// zones are set.
+	location       *time.Location
+	boardLocations []*time.Location
+	// freeze is the freeze calendar checked before starting, if any, and freezeOverridden is true
+	// if the schedule is frozen and the freeze was overridden.
+	freeze           *Freeze
+	freezeOverridden bool
+}
+
+const (
+	// BranchPending is the status of a branch which has not been deployed.
+	BranchPending = "pending"
+	// BranchDeployed is the status of a branch which has been deployed and passed its gates.
+	BranchDeployed = "deployed"
+	// BranchFailed is the status of a branch whose deployment or gates failed.
+	BranchFailed = "failed"
+)
+
+// DeployReport is the schedule and the result of a deployment.
+type DeployReport struct {
+	Repo       string          `json:"repo" yaml:"repo"`
+	TagName    string          `json:"tag_name" yaml:"tag_name"`
+	ReleaseURL string          `json:"release_url" yaml:"release_url"`
+	Branches   []*BranchResult `json:"branches" yaml:"branches"`
+	// DryRun is true if the branches were not updated, the changes being recorded instead.
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+// BranchResult is the estimated start time and the result of the deployment of a branch.
+type BranchResult struct {
+	Branch      string     `json:"branch" yaml:"branch"`
+	StartTime   time.Time  `json:"start_time" yaml:"start_time"`
+	TriggeredAt *time.Time `json:"triggered_at,omitempty" yaml:"triggered_at,omitempty"`
+	Status      string     `json:"status" yaml:"status"`
+	Error       string     `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 // NewDeploy initialize and return a new Deploy object.
@@ -44,7 +97,34 @@ func NewDeploy(
 	}
 }
 
-// Do is responsible for deploying the latest release.
+// SetStatePath sets the file where the deploy plan and its progress are persisted, so that an
+// interrupted deployment can be resumed. The plan is not persisted if the path is empty.
+func (r *Deploy) SetStatePath(statePath string) {
+	r.statePath = statePath
+}
+
+// SetChecksGate makes the deployment wait for the CI checks of each deployed branch to pass
+// before promoting the next branch. The gate is disabled if the timeout is not positive.
+func (r *Deploy) SetChecksGate(pollInterval, timeout time.Duration) {
+	r.checksPollInterval = pollInterval
+	r.checksTimeout = timeout
+}
+
+// SetDryRun makes the deployment record the changes to the host instead of making them and run
+// its schedule on a virtual clock, so that the whole timeline prints instantly. The gates are
+// skipped and the deploy state is not persisted.
+func (r *Deploy) SetDryRun() {
+	r.host = NewDryRunHost(r.host, r.c)
+	r.time = ergoTime.NewVirtual(r.time.Now())
+	r.dryRun = true
+}
+
+// SetRelease selects the release to deploy by its tag or its ID, instead of the latest release.
+func (r *Deploy) SetRelease(release string) {
+	r.release = release
+}
+
+// Do is responsible for deploying the latest release, or the release selected with SetRelease.
 func (r *Deploy) Do(
 	ctx context.Context,
 	releaseIntervalInput string,
@@ -53,7 +133,11 @@ func (r *Deploy) Do(
 	skipConfirm bool,
 	publishDraft bool,
 ) error {
-	release, err := r.host.LastRelease(ctx)
+	if err := r.checkNoUnfinishedPlan(); err != nil {
+		return err
+	}
+
+	release, err := r.targetRelease(ctx)
 	if err != nil {
 		return err
 	}
@@ -65,6 +149,8 @@ func (r *Deploy) Do(
 		if err = r.host.PublishRelease(ctx, release.ID); err != nil {
 			return fmt.Errorf("publishing latest found release (ID=%d, URL=%q): %w", release.ID, release.ReleaseURL, err)
 		}
+	} else if release.Draft && r.release != "" {
+		return fmt.Errorf("release %s (ID=%d) is a draft, publish it with --publish-draft", release.TagName, release.ID)
 	}
 
 	r.c.PrintColorizedLine("REPO: ", r.host.GetRepoName(), cli.WarningType)
@@ -75,52 +161,202 @@ func (r *Deploy) Do(
 	if err != nil {
 		return err
 	}
+	if r.waveIntervals != nil {
+		intervalDurations = r.waveIntervals
+	}
 
+	// without confirmation the deployment starts right away, unless it has a start time
 	releaseTime := *releaseTimer
+	switch {
+	case !r.startTime.IsZero():
+		releaseTime = r.startTime
+	case skipConfirm:
+		releaseTime = r.time.Now()
+	}
 
 	r.printReleaseTimeBoard(releaseTime, r.releaseBranches, intervalDurations)
 
-	if skipConfirm {
-		return r.deployToAllReleaseBranches(ctx, intervalDurations, release, allowForcePush)
+	if err = r.checkFreeze(releaseTime, r.releaseBranches, intervalDurations); err != nil {
+		return err
 	}
 
-	confirm, err := r.c.Confirmation("Deployment", "No deployment", "")
-	if err != nil {
-		return err
+	if skipConfirm && r.startTime.IsZero() {
+		if err = r.recordFreezeOverride(ctx, release, "deploy"); err != nil {
+			return err
+		}
+		if err = r.startPlan(release, allowForcePush, releaseTime, intervalDurations); err != nil {
+			return err
+		}
+		return r.deployToAllReleaseBranches(ctx, intervalDurations, release, allowForcePush)
 	}
-	if !confirm {
-		return nil
+
+	if !skipConfirm && !r.dryRun {
+		confirm, errConfirm := r.c.Confirmation("Deployment", "No deployment", "")
+		if errConfirm != nil {
+			return errConfirm
+		}
+		if !confirm {
+			return nil
+		}
 	}
 
-	if releaseTime.Before(time.Now()) {
+	if releaseTime.Before(r.time.Now()) {
 		return errors.New("deployment stopped since first released time has passed. Please run again")
 	}
 
-	untilReleaseTime := time.Until(releaseTime)
+	if err = r.recordFreezeOverride(ctx, release, "deploy"); err != nil {
+		return err
+	}
+
+	if err = r.startPlan(release, allowForcePush, releaseTime, intervalDurations); err != nil {
+		return err
+	}
+
+	untilReleaseTime := releaseTime.Sub(r.time.Now())
 	r.c.PrintLine("Deployment will start in", untilReleaseTime.String())
 	r.time.Sleep(untilReleaseTime)
 
 	return r.deployToAllReleaseBranches(ctx, intervalDurations, release, allowForcePush)
 }
 
+// Resume continues an interrupted deployment from the first branch which was not triggered.
+// The original schedule is kept, unless its next start time has passed, in which case the
+// remaining branches are re-planned to start now with the original intervals.
+func (r *Deploy) Resume(ctx context.Context, skipConfirm bool) error {
+	plan, err := loadDeployPlan(r.statePath)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return fmt.Errorf("no deployment to resume: state file %q not found", r.statePath)
+	}
+	if plan.Repo != r.host.GetRepoName() {
+		return fmt.Errorf("deployment in state file %q is for repo %s", r.statePath, plan.Repo)
+	}
+
+	pending := plan.pending()
+	if len(pending) == 0 {
+		r.c.PrintLine("Deployment of", plan.TagName, "is already complete.")
+		return r.clearPlan()
+	}
+
+	release, err := r.planRelease(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	if pending[0].StartTime.Before(r.time.Now()) {
+		r.c.PrintColorizedLine("", "The original schedule has passed, re-planning the remaining branches.", cli.WarningType)
+		plan.replan(r.time.Now())
+	}
+
+	r.c.PrintColorizedLine("REPO: ", r.host.GetRepoName(), cli.WarningType)
+	r.c.PrintLine("Resuming deployment of", plan.ReleaseURL)
+	r.c.PrintLine("Deployment start times are estimates.")
+
+	branches := make([]string, 0, len(pending))
+	intervalDurations := make([]time.Duration, 0, len(pending))
+	for i, b := range pending {
+		branches = append(branches, b.Branch)
+		if i < len(pending)-1 {
+			intervalDurations = append(intervalDurations, pending[i+1].StartTime.Sub(b.StartTime))
+		}
+	}
+	// The interval after the last branch is never used, it keeps the list non-empty.
+	intervalDurations = append(intervalDurations, 0)
+
+	r.printReleaseTimeBoard(pending[0].StartTime, branches, intervalDurations)
+
+	if err = r.checkFreeze(pending[0].StartTime, branches, intervalDurations); err != nil {
+		return err
+	}
+
+	if !skipConfirm && !r.dryRun {
+		confirm, errConfirm := r.c.Confirmation("Resume deployment", "No deployment", "")
+		if errConfirm != nil {
+			return errConfirm
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	if !r.dryRun {
+		r.plan = plan
+		if err = r.plan.save(r.statePath); err != nil {
+			return err
+		}
+	}
+
+	if untilReleaseTime := pending[0].StartTime.Sub(r.time.Now()); untilReleaseTime > 0 {
+		r.c.PrintLine("Deployment will start in", untilReleaseTime.String())
+		r.time.Sleep(untilReleaseTime)
+	}
+
+	r.releaseBranches = branches
+	if err = r.recordFreezeOverride(ctx, release, "deploy --resume"); err != nil {
+		return err
+	}
+
+	return r.deployToAllReleaseBranches(ctx, intervalDurations, release, plan.AllowForcePush)
+}
+
 func (r *Deploy) deployToAllReleaseBranches(
 	ctx context.Context,
 	intervalDurations []time.Duration,
 	release *ergo.Release,
 	allowForcePush bool,
-) error {
+) (err error) {
+	report := r.newDeployReport(release, intervalDurations)
+	defer func() {
+		for _, b := range report.Branches {
+			if err != nil && b.Status == BranchPending && b.TriggeredAt != nil {
+				b.Status, b.Error = BranchFailed, err.Error()
+			}
+		}
+		r.c.PrintObject(report)
+	}()
+
 	for i, branch := range r.releaseBranches {
+		if err = r.confirmWave(branch); err != nil {
+			return err
+		}
+
 		r.c.PrintLine("Deploying", r.time.Now().Format("15:04:05"), branch)
+		result := report.Branches[i]
+
+		previousSHA, err := r.rollbackSHA(ctx, branch)
+		if err != nil {
+			return err
+		}
 
 		if errRelease := r.host.UpdateBranchFromTag(ctx, release.TagName, branch, allowForcePush); errRelease != nil {
+			result.Status, result.Error = BranchFailed, errRelease.Error()
 			return errRelease
 		}
 		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Triggered Successfully")
+		triggeredAt := r.time.Now()
+		result.TriggeredAt = &triggeredAt
+
+		if err = r.waitForChecks(ctx, branch); err != nil {
+			return err
+		}
 
-		err := r.updateHostReleaseBody(ctx, r.releaseBodyBranches, branch, r.releaseBodyFind, r.releaseBodyReplace)
+		if err = r.waitForHealthCheck(ctx, branch, release.TagName); err != nil {
+			return r.rollback(ctx, branch, previousSHA, err)
+		}
+
+		// The branch is only marked as deployed, in the plan and in the release body, once the
+		// gates have passed, so that a halted deployment resumes from it.
+		if err = r.markTriggered(branch); err != nil {
+			return err
+		}
+
+		err = r.updateHostReleaseBody(ctx, release, r.releaseBodyBranches, branch, r.releaseBodyFind, r.releaseBodyReplace)
 		if err != nil {
 			return err
 		}
+		result.Status = BranchDeployed
 
 		// Don't sleep after the last deployment
 		if i < (len(r.releaseBranches) - 1) {
@@ -128,6 +364,197 @@ func (r *Deploy) deployToAllReleaseBranches(
 			r.time.Sleep(intervalDuration)
 		}
 	}
+	return r.clearPlan()
+}
+
+// newDeployReport creates the report of the deployment of the release branches, estimating the
+// start times from now.
+func (r *Deploy) newDeployReport(release *ergo.Release, intervalDurations []time.Duration) *DeployReport {
+	report := &DeployReport{
+		Repo:       r.host.GetRepoName(),
+		TagName:    release.TagName,
+		ReleaseURL: release.ReleaseURL,
+		DryRun:     r.dryRun,
+	}
+	startTime := r.time.Now()
+	for i, branch := range r.releaseBranches {
+		report.Branches = append(report.Branches, &BranchResult{Branch: branch, StartTime: startTime, Status: BranchPending})
+		startTime = startTime.Add(intervalDurations[i%len(intervalDurations)])
+	}
+	return report
+}
+
+// waitForChecks waits for the CI checks of the deployed branch's head, if the gate is enabled.
+func (r *Deploy) waitForChecks(ctx context.Context, branch string) error {
+	if r.checksTimeout <= 0 {
+		return nil
+	}
+	if r.dryRun {
+		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Would wait up to", r.checksTimeout.String(), "for the checks of", branch)
+		return nil
+	}
+
+	ref, err := r.host.GetRef(ctx, branch)
+	if err != nil {
+		return err
+	}
+	r.c.PrintLine(r.time.Now().Format("15:04:05"), "Waiting for checks of", branch, ref.SHA)
+
+	checksCtx, cancel := context.WithTimeout(ctx, r.checksTimeout)
+	defer cancel()
+
+	report, err := r.host.WaitForChecks(checksCtx, ref.SHA, r.checksPollInterval)
+	if err == nil && report.State == ergo.CheckStateSuccess {
+		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Checks passed")
+		return nil
+	}
+	if report != nil {
+		r.printChecksReport(report)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) && (report == nil || len(report.Checks) == 0) {
+		return fmt.Errorf("deployment halted: no checks of %s (%s) were reported within %s", branch, ref.SHA, r.checksTimeout)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("deployment halted: checks of %s (%s) did not complete within %s", branch, ref.SHA, r.checksTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("deployment halted: waiting for checks of %s (%s): %w", branch, ref.SHA, err)
+	}
+	return fmt.Errorf("deployment halted: checks of %s (%s) failed", branch, ref.SHA)
+}
+
+// rollbackSHA returns the commit of the branch before the deployment, if the branch is rolled back
+// when its health check fails.
+func (r *Deploy) rollbackSHA(ctx context.Context, branch string) (string, error) {
+	healthCheck, ok := r.healthChecks[strings.ToLower(branch)]
+	if !ok || !healthCheck.Rollback {
+		return "", nil
+	}
+	ref, err := r.host.GetRef(ctx, branch)
+	if err != nil {
+		return "", fmt.Errorf("error getting the commit of %s to roll back to: %w", branch, err)
+	}
+	return ref.SHA, nil
+}
+
+// rollback moves the branch back to its previous commit after its health check failed and
+// returns the error halting the deployment.
+func (r *Deploy) rollback(ctx context.Context, branch, previousSHA string, healthErr error) error {
+	if previousSHA == "" {
+		return fmt.Errorf("deployment halted: %w", healthErr)
+	}
+
+	r.c.PrintColorizedLine("Rolling back ", fmt.Sprintf("%s to %s", branch, previousSHA), cli.WarningType)
+	if err := r.host.UpdateBranchToSHA(ctx, previousSHA, branch, true); err != nil {
+		return fmt.Errorf("deployment halted: %v, rollback of %s failed: %w", healthErr, branch, err)
+	}
+	return fmt.Errorf("deployment halted: %w, %s rolled back to %s", healthErr, branch, previousSHA)
+}
+
+// printChecksReport prints the checks which did not succeed.
+func (r *Deploy) printChecksReport(report *ergo.ChecksReport) {
+	var rows [][]string
+	for _, check := range report.Checks {
+		if check.State != ergo.CheckStateSuccess {
+			rows = append(rows, []string{check.Name, string(check.State), check.URL})
+		}
+	}
+	r.c.PrintTable([]string{"Check", "State", "URL"}, rows)
+}
+
+// targetRelease returns the release selected by its tag or its ID, or the latest release if none
+// is selected.
+func (r *Deploy) targetRelease(ctx context.Context) (*ergo.Release, error) {
+	if r.release == "" {
+		return r.host.LastRelease(ctx)
+	}
+
+	release, err := r.host.GetReleaseByTag(ctx, r.release)
+	if err != nil || release != nil {
+		return release, err
+	}
+
+	if id, errParse := strconv.ParseInt(r.release, 10, 64); errParse == nil {
+		releases, err := r.host.ListReleases(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range releases {
+			if release.ID == id {
+				return release, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("release %s not found", r.release)
+}
+
+// planRelease returns the release of the deployment plan, found by its tag or, if the tag now
+// belongs to another release, by its ID, so that its body is updated with the badges.
+func (r *Deploy) planRelease(ctx context.Context, plan *DeployPlan) (*ergo.Release, error) {
+	release, err := r.host.GetReleaseByTag(ctx, plan.TagName)
+	if err != nil {
+		return nil, err
+	}
+	if release != nil && release.ID == plan.ReleaseID {
+		return release, nil
+	}
+
+	releases, err := r.host.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.ID == plan.ReleaseID {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("release %s (ID=%d) of the deployment not found", plan.TagName, plan.ReleaseID)
+}
+
+// checkNoUnfinishedPlan returns an error if the state file holds a deployment which was not completed.
+func (r *Deploy) checkNoUnfinishedPlan() error {
+	if r.statePath == "" {
+		return nil
+	}
+	plan, err := loadDeployPlan(r.statePath)
+	if err != nil {
+		return err
+	}
+	if plan != nil && len(plan.pending()) > 0 {
+		return fmt.Errorf("unfinished deployment of %s found in %q: run deploy with --resume or remove the file",
+			plan.TagName, r.statePath)
+	}
+	return nil
+}
+
+// startPlan persists the plan of the deployment which is about to start.
+func (r *Deploy) startPlan(release *ergo.Release, allowForcePush bool, releaseTime time.Time, intervalDurations []time.Duration) error {
+	if r.statePath == "" || r.dryRun {
+		return nil
+	}
+	r.plan = newDeployPlan(r.host.GetRepoName(), release, allowForcePush, releaseTime, r.releaseBranches, intervalDurations)
+	return r.plan.save(r.statePath)
+}
+
+// markTriggered persists that the branch has been triggered.
+func (r *Deploy) markTriggered(branch string) error {
+	if r.plan == nil {
+		return nil
+	}
+	r.plan.markTriggered(branch, r.time.Now())
+	return r.plan.save(r.statePath)
+}
+
+// clearPlan removes the state file of a completed deployment.
+func (r *Deploy) clearPlan() error {
+	if r.statePath == "" || r.dryRun {
+		return nil
+	}
+	r.plan = nil
+	if err := os.Remove(r.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing deploy state file: %w", err)
+	}
 	return nil
 }
 
@@ -150,33 +577,57 @@ func (r *Deploy) calculateReleaseTime(releaseInterval, releaseOffset string) ([]
 	if len(intervalDurations) == 0 {
 		return []time.Duration{}, nil, fmt.Errorf("missing required interval durations")
 	}
-	releaseTime := time.Now().Add(offsetDuration)
+	releaseTime := r.time.Now().Add(offsetDuration)
 	return intervalDurations, &releaseTime, nil
 }
 
-// printReleaseTimeBoard print the release time board.
+// printReleaseTimeBoard print the release time board, with a start time column per board time
+// zone if any, or else in the time zone of the schedule. The date is printed too if the start
+// time or the board time zones are set.
 func (r *Deploy) printReleaseTimeBoard(releaseTime time.Time, releaseBranches []string, intervalDurations []time.Duration) {
-	var times [][]string
+	layout := "15:04 MST"
+	if !r.startTime.IsZero() || len(r.boardLocations) > 0 {
+		layout = "Mon 02 Jan 15:04 MST"
+	}
 
-	for i, branch := range releaseBranches {
-		interval := intervalDurations[i%len(intervalDurations)]
-		timesRow := []string{branch, releaseTime.Format("15:04 MST")}
-		releaseTime = releaseTime.Add(interval)
+	var times [][]string
+	for i, startTime := range releaseTimes(releaseTime, releaseBranches, intervalDurations) {
+		timesRow := []string{releaseBranches[i]}
+		if len(r.boardLocations) == 0 {
+			if r.location != nil {
+				startTime = startTime.In(r.location)
+			}
+			timesRow = append(timesRow, startTime.Format(layout))
+		}
+		for _, loc := range r.boardLocations {
+			timesRow = append(timesRow, startTime.In(loc).Format(layout))
+		}
 		times = append(times, timesRow)
 	}
 
 	headers := []string{"Branch", "Start Time"}
+	if len(r.boardLocations) > 0 {
+		headers = headers[:1]
+		for _, loc := range r.boardLocations {
+			headers = append(headers, loc.String())
+		}
+	}
 	r.c.PrintTable(headers, times)
 }
 
 // updateHostReleaseBody update the host release body.
-func (r *Deploy) updateHostReleaseBody(ctx context.Context, branchMap map[string]string, branch, suffixFind, suffixReplace string) error {
+func (r *Deploy) updateHostReleaseBody(
+	ctx context.Context,
+	release *ergo.Release,
+	branchMap map[string]string,
+	branch, suffixFind, suffixReplace string,
+) error {
 	branchText, ok := branchMap[branch]
 	if !ok {
 		branchText = branch
 	}
 	if suffixFind != "" {
-		err := r.updateReleaseBodySuffix(ctx, branchText, suffixFind, suffixReplace)
+		err := r.updateReleaseBodySuffix(ctx, release, branchText, suffixFind, suffixReplace)
 		if err != nil {
 			return err
 		}
@@ -184,20 +635,20 @@ func (r *Deploy) updateHostReleaseBody(ctx context.Context, branchMap map[string
 	return nil
 }
 
-// updateReleaseBodySuffix update the release body suffixes.
-func (r *Deploy) updateReleaseBodySuffix(ctx context.Context, branchText, suffixFind, suffixReplace string) error {
-	t := time.Now()
-	release, err := r.host.LastRelease(ctx)
-	if err != nil {
-		return err
-	}
+// updateReleaseBodySuffix update the badge of the branch in the body of the deployed release. The
+// release is not edited if its body has no badge of the branch.
+func (r *Deploy) updateReleaseBodySuffix(ctx context.Context, release *ergo.Release, branchText, suffixFind, suffixReplace string) error {
+	t := r.time.Now()
 
 	findText := fmt.Sprintf("%s ![](https://img.shields.io/badge/released%s)", branchText, suffixFind)
 	replaceText := fmt.Sprintf("%s ![](https://img.shields.io/badge/released-%d_%s_%d_%02d:%02d%s)",
 		branchText, t.Day(), t.Month(), t.Year(), t.Hour(), t.Minute(), suffixReplace)
 	newBody := strings.Replace(release.Body, findText, replaceText, -1)
+	if newBody == release.Body {
+		return nil
+	}
 	release.Body = newBody
-	_, err = r.host.EditRelease(ctx, release)
+	_, err := r.host.EditRelease(ctx, release)
 	if err != nil {
 		return err
 	}
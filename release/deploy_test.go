@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -412,6 +415,7 @@ func TestPrintReleaseTimeBoard_NonLinearIntervalHandling(t *testing.T) {
 			cliMock := &mock.CLI{}
 			deploy := &Deploy{
 				c:               cliMock,
+				time:            mock.NewMockedTime(time.Now()),
 				releaseBranches: test.branches,
 			}
 			intervalDurations, releaseTimer, err := deploy.calculateReleaseTime(test.intervals, "1ms")
@@ -542,3 +546,478 @@ func TestDeployToAllReleaseBranches_NonLinearIntervalHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestDoShouldPersistAndRemoveTheDeployPlan(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	triggered := 0
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			plan, err := loadDeployPlan(statePath)
+			if err != nil || plan == nil {
+				t.Fatalf("expected the plan to be persisted before deploying, got %v, %v", plan, err)
+			}
+			if got := len(plan.pending()); got != 2-triggered {
+				t.Errorf("expected %d pending branches, got %d", 2-triggered, got)
+			}
+			triggered++
+			return nil
+		},
+	}
+
+	deploy := NewDeploy(&mock.CLI{}, host, "baseBranch", "", "", []string{"branch1", "branch2"}, map[string]string{})
+	deploy.SetStatePath(statePath)
+
+	if err := deploy.Do(ctx, "1ms", "1ms", false, true, false); err != nil {
+		t.Fatalf("NewDeploy().Do() returned error: %v", err)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed after a complete deployment")
+	}
+}
+
+func TestDoShouldReturnErrorForUnfinishedDeployPlan(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	plan := newDeployPlan("", &ergo.Release{TagName: "0.9.0"}, false, time.Now(), []string{"branch1"}, []time.Duration{time.Minute})
+	if err := plan.save(statePath); err != nil {
+		t.Fatal(err)
+	}
+
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+	}
+	deploy := NewDeploy(&mock.CLI{}, host, "baseBranch", "", "", []string{"branch1"}, map[string]string{})
+	deploy.SetStatePath(statePath)
+
+	if err := deploy.Do(ctx, "1ms", "1ms", false, true, false); err == nil {
+		t.Error("expected Do to return error for an unfinished deployment")
+	}
+}
+
+func TestResumeShouldContinueFromTheFirstBranchNotTriggered(t *testing.T) {
+	now := time.Date(2022, 8, 4, 13, 37, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		planStart      time.Time
+		expectedPrints []string
+	}{
+		{
+			name:      "original schedule",
+			planStart: now.Add(-10 * time.Minute),
+			expectedPrints: []string{
+				"Deploying 13:37:00 branch2",
+				"Deploying 13:42:00 branch3",
+			},
+		},
+		{
+			name:      "passed schedule is re-planned",
+			planStart: now.Add(-time.Hour),
+			expectedPrints: []string{
+				"Deploying 13:37:00 branch2",
+				"Deploying 13:42:00 branch3",
+			},
+		},
+		{
+			name:      "future schedule",
+			planStart: now.Add(-5 * time.Minute),
+			expectedPrints: []string{
+				"Deploying 13:42:00 branch2",
+				"Deploying 13:47:00 branch3",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			statePath := filepath.Join(t.TempDir(), "state.json")
+			plan := newDeployPlan("o/r", &ergo.Release{TagName: "1.0.0"}, false, test.planStart,
+				[]string{"branch1", "branch2", "branch3"}, []time.Duration{10 * time.Minute, 5 * time.Minute})
+			plan.markTriggered("branch1", test.planStart)
+			if err := plan.save(statePath); err != nil {
+				t.Fatal(err)
+			}
+
+			cliMock := &mock.CLI{}
+			host := &mock.RepositoryClient{
+				GetRepoNameFn: func() string { return "o/r" },
+				GetReleaseByTagFn: func(tagName string) (*ergo.Release, error) {
+					return &ergo.Release{TagName: tagName}, nil
+				},
+			}
+			deploy := &Deploy{c: cliMock, host: host, time: mock.NewMockedTime(now)}
+			deploy.SetStatePath(statePath)
+
+			if err := deploy.Resume(ctx, true); err != nil {
+				t.Fatalf("Resume() returned error: %v", err)
+			}
+
+			var deploying []string
+			for _, line := range cliMock.PrintLines {
+				if strings.HasPrefix(line, "Deploying ") {
+					deploying = append(deploying, line)
+				}
+			}
+			if !reflect.DeepEqual(test.expectedPrints, deploying) {
+				t.Errorf("expected %v to equal %v", test.expectedPrints, deploying)
+			}
+			if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+				t.Errorf("expected state file to be removed after a complete deployment")
+			}
+		})
+	}
+}
+
+func TestResumeShouldFindTheReleaseOfThePlanByID(t *testing.T) {
+	const body = "gr ![](https://img.shields.io/badge/released-No-red.svg)"
+	tests := []struct {
+		name     string
+		releases []*ergo.Release
+		wantErr  bool
+	}{
+		{name: "tag moved to another release", releases: []*ergo.Release{{ID: 8, TagName: "1.0.0"}, {ID: 7, TagName: "1.0.0-old", Body: body}}},
+		{name: "release deleted", releases: []*ergo.Release{{ID: 8, TagName: "1.0.0"}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2022, 8, 4, 13, 37, 0, 0, time.UTC)
+			statePath := filepath.Join(t.TempDir(), "state.json")
+			plan := newDeployPlan("o/r", &ergo.Release{ID: 7, TagName: "1.0.0"}, false, now,
+				[]string{"branch1"}, []time.Duration{time.Minute})
+			if err := plan.save(statePath); err != nil {
+				t.Fatal(err)
+			}
+
+			deployed := 0
+			var edited *ergo.Release
+			host := &mock.RepositoryClient{
+				GetRepoNameFn: func() string { return "o/r" },
+				GetReleaseByTagFn: func(tagName string) (*ergo.Release, error) {
+					return test.releases[0], nil
+				},
+				ListReleasesFn: func() ([]*ergo.Release, error) {
+					return test.releases, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					deployed++
+					return nil
+				},
+			}
+			host.EditReleaseFn = func() (*ergo.Release, error) {
+				edited = test.releases[len(test.releases)-1]
+				return edited, nil
+			}
+			deploy := &Deploy{
+				c:                   &mock.CLI{},
+				host:                host,
+				time:                mock.NewMockedTime(now),
+				releaseBodyFind:     "-No-red.svg",
+				releaseBodyReplace:  "-green.svg",
+				releaseBodyBranches: map[string]string{"branch1": "gr"},
+			}
+			deploy.SetStatePath(statePath)
+
+			err := deploy.Resume(ctx, true)
+			if test.wantErr {
+				if err == nil || deployed != 0 {
+					t.Errorf("expected the resume to fail before deploying, got %v and %d deployments", err, deployed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resume() returned error: %v", err)
+			}
+			if edited == nil || edited.ID != 7 || strings.Contains(edited.Body, "-No-red.svg") {
+				t.Errorf("expected the badge of the planned release to be updated, got %+v", edited)
+			}
+		})
+	}
+}
+
+func TestResumeShouldReturnErrorWithoutDeployPlan(t *testing.T) {
+	deploy := NewDeploy(&mock.CLI{}, &mock.RepositoryClient{}, "baseBranch", "", "", nil, map[string]string{})
+	deploy.SetStatePath(filepath.Join(t.TempDir(), "state.json"))
+
+	if err := deploy.Resume(ctx, true); err == nil {
+		t.Error("expected Resume to return error without a deploy plan")
+	}
+}
+
+func TestDoShouldGateEachBranchOnChecks(t *testing.T) {
+	tests := []struct {
+		name            string
+		report          *ergo.ChecksReport
+		err             error
+		wantErr         bool
+		wantErrText     string
+		wantDeployments int
+	}{
+		{name: "green checks", report: &ergo.ChecksReport{State: ergo.CheckStateSuccess}, wantDeployments: 2},
+		{
+			name: "red checks",
+			report: &ergo.ChecksReport{State: ergo.CheckStateFailure, Checks: []*ergo.Check{
+				{Name: "build", State: ergo.CheckStateFailure, URL: "url"},
+			}},
+			wantErr:         true,
+			wantDeployments: 1,
+		},
+		{
+			name:            "timeout",
+			report:          &ergo.ChecksReport{State: ergo.CheckStatePending, Checks: []*ergo.Check{{Name: "build", State: ergo.CheckStatePending}}},
+			err:             context.DeadlineExceeded,
+			wantErr:         true,
+			wantErrText:     "did not complete",
+			wantDeployments: 1,
+		},
+		{
+			name:            "no checks reported",
+			report:          &ergo.ChecksReport{State: ergo.CheckStatePending},
+			err:             context.DeadlineExceeded,
+			wantErr:         true,
+			wantErrText:     "no checks of branch1",
+			wantDeployments: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployments := 0
+			host := &mock.RepositoryClient{
+				LastReleaseFn: func() (*ergo.Release, error) {
+					return &ergo.Release{TagName: "1.0.0"}, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					deployments++
+					return nil
+				},
+				GetRefFn: func(string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "sha"}, nil
+				},
+				WaitForChecksFn: func() (*ergo.ChecksReport, error) {
+					return test.report, test.err
+				},
+			}
+			cliMock := &mock.CLI{}
+
+			deploy := NewDeploy(cliMock, host, "baseBranch", "", "", []string{"branch1", "branch2"}, map[string]string{})
+			deploy.SetChecksGate(time.Millisecond, time.Minute)
+
+			err := deploy.Do(context.Background(), "1ms", "1ms", false, true, false)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Do() returned error %v, want error: %t", err, test.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.wantErrText) {
+				t.Errorf("expected the error to contain %q, got %v", test.wantErrText, err)
+			}
+			if deployments != test.wantDeployments {
+				t.Errorf("expected %d deployments, got %d", test.wantDeployments, deployments)
+			}
+			if test.wantErr && len(cliMock.PrintTableCalls) != 2 {
+				t.Errorf("expected the checks report to be printed")
+			}
+		})
+	}
+}
+
+func TestDoShouldPrintTheDeployReport(t *testing.T) {
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0", ReleaseURL: "url"}, nil
+		},
+		GetRepoNameFn: func() string {
+			return "o/r"
+		},
+	}
+	// the first branch is deployed, the second one fails
+	host.UpdateBranchFromTagFn = func() error {
+		host.UpdateBranchFromTagFn = func() error { return errors.New("diverged") }
+		return nil
+	}
+	c := &mock.CLI{}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	deploy := &Deploy{c: c, host: host, time: mock.NewMockedTime(now), releaseBranches: []string{"branch1", "branch2", "branch3"}}
+
+	if err := deploy.Do(context.Background(), "10m", "1ms", false, true, false); err == nil {
+		t.Fatal("Do() should return the error of the second branch")
+	}
+
+	if len(c.PrintObjectCalls) != 1 {
+		t.Fatalf("expected one report, got %d", len(c.PrintObjectCalls))
+	}
+	report := c.PrintObjectCalls[0].(*DeployReport)
+	if report.Repo != "o/r" || report.TagName != "1.0.0" || len(report.Branches) != 3 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	wantStatuses := []string{BranchDeployed, BranchFailed, BranchPending}
+	for i, b := range report.Branches {
+		if b.Status != wantStatuses[i] {
+			t.Errorf("branch %s has status %s; want %s", b.Branch, b.Status, wantStatuses[i])
+		}
+		if want := now.Add(time.Duration(i) * 10 * time.Minute); !b.StartTime.Equal(want) {
+			t.Errorf("branch %s starts at %v; want %v", b.Branch, b.StartTime, want)
+		}
+	}
+	if report.Branches[0].TriggeredAt == nil || report.Branches[1].Error != "diverged" {
+		t.Errorf("unexpected results %+v %+v", report.Branches[0], report.Branches[1])
+	}
+}
+
+func TestDoShouldRunTheTimelineOnAVirtualClockInDryRun(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0", Body: "release-gr ![](https://img.shields.io/badge/released-No-red.svg) " +
+				"release-mx ![](https://img.shields.io/badge/released-No-red.svg)"}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			return errors.New("branches should not be updated in a dry run")
+		},
+		EditReleaseFn: func() (*ergo.Release, error) {
+			return nil, errors.New("the release should not be edited in a dry run")
+		},
+	}
+	c := &mock.CLI{ConfirmationFn: func() (bool, error) {
+		return false, nil
+	}}
+
+	deploy := NewDeploy(c, host, "baseBranch", "-No-red.svg", "-green.svg", []string{"release-gr", "release-mx"}, map[string]string{})
+	deploy.SetStatePath(statePath)
+	deploy.SetChecksGate(time.Second, time.Hour)
+	deploy.SetDryRun()
+
+	start := time.Now()
+	if err := deploy.Do(ctx, "2h", "30m", false, false, false); err != nil {
+		t.Fatalf("Do should not return the error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected the timeline to run instantly, took %s", elapsed)
+	}
+	if c.ConfirmationCalls != 0 {
+		t.Errorf("expected no confirmation, got %d", c.ConfirmationCalls)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("expected no state file in a dry run")
+	}
+
+	calls := deploy.host.(*DryRunHost).Calls()
+	if len(calls) != 4 {
+		t.Fatalf("expected the updates and the edits of both branches, got %+v", calls)
+	}
+
+	report := c.PrintObjectCalls[0].(*DeployReport)
+	if !report.DryRun || report.Branches[1].TriggeredAt.Sub(*report.Branches[0].TriggeredAt) != 2*time.Hour {
+		t.Errorf("expected the branches to be triggered 2h apart on the virtual clock, got %+v", report.Branches)
+	}
+}
+
+func TestDoShouldDeployTheSelectedRelease(t *testing.T) {
+	releases := []*ergo.Release{
+		{ID: 3, TagName: "1.2.0"},
+		{ID: 2, TagName: "1.1.0", Body: "release-gr ![](https://img.shields.io/badge/released-No-red.svg) " +
+			"release-mx ![](https://img.shields.io/badge/released-No-red.svg)"},
+	}
+	tests := map[string]struct {
+		release string
+		wantTag string
+		wantErr bool
+	}{
+		"by tag":    {release: "1.1.0", wantTag: "1.1.0"},
+		"by id":     {release: "2", wantTag: "1.1.0"},
+		"not found": {release: "0.9.0", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var edits int
+			host := &mock.RepositoryClient{
+				LastReleaseFn: func() (*ergo.Release, error) {
+					return nil, errors.New("the latest release should not be fetched")
+				},
+				GetReleaseByTagFn: func(tagName string) (*ergo.Release, error) {
+					for _, r := range releases {
+						if r.TagName == tagName {
+							copied := *r
+							return &copied, nil
+						}
+					}
+					return nil, nil
+				},
+				ListReleasesFn: func() ([]*ergo.Release, error) {
+					copied := make([]*ergo.Release, 0, len(releases))
+					for _, r := range releases {
+						r := *r
+						copied = append(copied, &r)
+					}
+					return copied, nil
+				},
+			}
+			c := &mock.CLI{}
+
+			deploy := NewDeploy(c, host, "baseBranch", "-No-red.svg", "-green.svg", []string{"release-gr", "release-mx"}, map[string]string{})
+			deploy.SetRelease(tt.release)
+			deploy.SetDryRun()
+			err := deploy.Do(ctx, "1m", "1m", false, true, false)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected Do to return error for a release which does not exist")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Do should not return the error: %v", err)
+			}
+
+			report := c.PrintObjectCalls[0].(*DeployReport)
+			if report.TagName != tt.wantTag {
+				t.Errorf("expected the release %s to be deployed, got %s", tt.wantTag, report.TagName)
+			}
+			for _, call := range deploy.host.(*DryRunHost).Calls() {
+				if call.Method == "EditRelease" {
+					edits++
+				}
+			}
+			if edits != 2 {
+				t.Errorf("expected the badges of both branches to be updated, got %d edits", edits)
+			}
+		})
+	}
+}
+
+func TestUpdateReleaseBodySuffixShouldKeepTheBadgesOfTheDeployedBranches(t *testing.T) {
+	var bodies []string
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return nil, errors.New("the latest release should not be fetched")
+		},
+	}
+	recorder := &bodyRecorder{RepositoryClient: host, bodies: &bodies}
+	deploy := &Deploy{c: &mock.CLI{}, host: recorder, time: mock.NewMockedTime(time.Date(2026, 10, 20, 14, 5, 0, 0, time.UTC))}
+	release := &ergo.Release{TagName: "1.1.0", Body: "gr ![](https://img.shields.io/badge/released-No-red.svg) " +
+		"mx ![](https://img.shields.io/badge/released-No-red.svg)"}
+
+	for _, branch := range []string{"gr", "mx", "pe"} {
+		if err := deploy.updateReleaseBodySuffix(ctx, release, branch, "-No-red.svg", "-green.svg"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "gr ![](https://img.shields.io/badge/released-20_October_2026_14:05-green.svg) " +
+		"mx ![](https://img.shields.io/badge/released-20_October_2026_14:05-green.svg)"
+	if len(bodies) != 2 || bodies[1] != want {
+		t.Errorf("expected two edits of the body, the last one being %q, got %q", want, bodies)
+	}
+}
+
+// bodyRecorder records the bodies of the edited releases.
+type bodyRecorder struct {
+	*mock.RepositoryClient
+	bodies *[]string
+}
+
+func (b *bodyRecorder) EditRelease(ctx context.Context, release *ergo.Release) (*ergo.Release, error) {
+	*b.bodies = append(*b.bodies, release.Body)
+	return release, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/beatlabs/ergo"
 	"github.com/beatlabs/ergo/cli"
+	ergoTime "github.com/beatlabs/ergo/time"
 )
 
 // Draft is responsible for creating the draft release.
@@ -17,6 +20,33 @@ type Draft struct {
 	releaseBodyPrefix   string
 	releaseBranches     []string
 	releaseBodyBranches map[string]string
+	bodyTemplate        *template.Template
+	pullRequestNotes    bool
+	labelSections       map[string]string
+	conventionalCommits bool
+	dryRun              bool
+	time                ergo.Time
+	// publish is true if the draft is published once created, freeze being checked first if set.
+	publish bool
+	freeze  *Freeze
+	// diff is the diff of the release branches set with SetDiff, if any.
+	diff []*ergo.StatusReport
+}
+
+// DraftReport is the result of drafting a release.
+type DraftReport struct {
+	Repo       string `json:"repo" yaml:"repo"`
+	Name       string `json:"name" yaml:"name"`
+	Version    string `json:"version" yaml:"version"`
+	BaseBranch string `json:"base_branch" yaml:"base_branch"`
+	Body       string `json:"body" yaml:"body"`
+	Created    bool   `json:"created" yaml:"created"`
+	// Truncated is true if the body misses commits because the comparison was truncated.
+	Truncated bool `json:"truncated" yaml:"truncated"`
+	// DryRun is true if the draft was not created, its creation being recorded instead.
+	DryRun bool `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+	// Published is true if the draft was published once created.
+	Published bool `json:"published,omitempty" yaml:"published,omitempty"`
 }
 
 // NewDraft initialize and return a new Draft object.
@@ -34,39 +64,193 @@ func NewDraft(
 		releaseBodyPrefix:   releaseBodyPrefix,
 		releaseBranches:     releaseBranches,
 		releaseBodyBranches: releaseBodyBranches,
+		time:                ergoTime.Time{},
 	}
 }
 
+// SetBodyTemplate sets the template of the release body, replacing the default layout.
+func (d *Draft) SetBodyTemplate(bodyTemplate *template.Template) {
+	d.bodyTemplate = bodyTemplate
+}
+
+// SetConventionalCommits makes the release body group the commits by their Conventional Commits
+// type, listing the breaking changes first.
+func (d *Draft) SetConventionalCommits() {
+	d.conventionalCommits = true
+}
+
+// SetDiff makes the draft use the diff of the release branches, such as the one the version was
+// inferred from, instead of comparing the branches again.
+func (d *Draft) SetDiff(diff []*ergo.StatusReport) {
+	d.diff = diff
+}
+
+// SetDryRun makes the draft record its creation on the host instead of creating it, without
+// asking for confirmation.
+func (d *Draft) SetDryRun() {
+	d.host = NewDryRunHost(d.host, d.c)
+	d.dryRun = true
+}
+
+// SetPublish makes the draft be published once created. The release branches are checked against
+// the freeze, if any, as if they were deployed now.
+func (d *Draft) SetPublish(freeze *Freeze) {
+	d.publish = true
+	d.freeze = freeze
+}
+
 // Create is responsible to create a new draft release.
 func (d *Draft) Create(ctx context.Context, releaseName, tagName string, skipConfirm bool) error {
-	diff, err := d.host.DiffCommits(ctx, d.releaseBranches, d.baseBranch)
+	diff := d.diff
+	if diff == nil {
+		var err error
+		if diff, err = d.host.DiffCommits(ctx, d.releaseBranches, d.baseBranch); err != nil {
+			return err
+		}
+	}
+
+	releaseBody, err := d.renderReleaseBody(ctx, diff, tagName)
 	if err != nil {
 		return err
 	}
 
-	releaseBody := d.releaseBody(diff, d.releaseBodyPrefix, d.releaseBodyBranches)
+	if d.publish && d.freeze != nil {
+		now := d.time.Now()
+		startTimes := make([]time.Time, len(d.releaseBranches))
+		for i := range startTimes {
+			startTimes[i] = now
+		}
+		overridden, errFreeze := d.freeze.Check(d.c, d.releaseBranches, startTimes)
+		if errFreeze != nil {
+			return errFreeze
+		}
+		if overridden {
+			releaseBody = d.freeze.RecordOverride(releaseBody, "draft --publish", now)
+		}
+	}
 
 	d.c.PrintColorizedLine("REPO: ", d.host.GetRepoName(), cli.WarningType)
 	d.c.PrintLine(releaseBody)
 
-	if skipConfirm {
-		return d.host.CreateDraftRelease(ctx, releaseName, tagName, releaseBody, d.baseBranch)
+	report := &DraftReport{
+		Repo:       d.host.GetRepoName(),
+		Name:       releaseName,
+		Version:    tagName,
+		BaseBranch: d.baseBranch,
+		Body:       releaseBody,
+		DryRun:     d.dryRun,
+	}
+	if len(diff) >= 1 && len(diff[0].Behind) < diff[0].BehindBy {
+		report.Truncated = true
+		d.c.PrintColorizedLine("TRUNCATED: ",
+			fmt.Sprintf("the release body lists %d of %d commits", len(diff[0].Behind), diff[0].BehindBy),
+			cli.WarningType)
+	}
+
+	if !skipConfirm && !d.dryRun {
+		message := "Draft the release"
+		if d.publish {
+			message = "Draft and publish the release"
+		}
+		confirm, errConfirm := d.c.Confirmation(
+			message,
+			"No draft",
+			"The draft release is ready",
+		)
+		if errConfirm != nil {
+			return fmt.Errorf("confirmation dialog error: %w", errConfirm)
+		}
+
+		if !confirm {
+			d.c.PrintObject(report)
+			return nil
+		}
+	}
+
+	if err = d.host.CreateDraftRelease(ctx, releaseName, tagName, releaseBody, d.baseBranch); err != nil {
+		return err
+	}
+	report.Created = !d.dryRun
+
+	if d.publish {
+		if err = d.publishDraft(ctx, tagName); err != nil {
+			return err
+		}
+		report.Published = !d.dryRun
 	}
+	d.c.PrintObject(report)
 
-	confirm, err := d.c.Confirmation(
-		"Draft the release",
-		"No draft",
-		"The draft release is ready",
-	)
+	return nil
+}
+
+// publishDraft publishes the draft release of the tag.
+func (d *Draft) publishDraft(ctx context.Context, tagName string) error {
+	releases, err := d.host.ListReleases(ctx)
 	if err != nil {
-		return fmt.Errorf("confirmation dialog error: %w", err)
+		return err
+	}
+	for _, release := range releases {
+		if release.Draft && release.TagName == tagName {
+			if err = d.host.PublishRelease(ctx, release.ID); err != nil {
+				return fmt.Errorf("publishing the draft release %s (ID=%d): %w", tagName, release.ID, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("draft release %s not found to publish", tagName)
+}
+
+// renderReleaseBody renders the release body with the template, if one is set, or the default layout.
+func (d *Draft) renderReleaseBody(ctx context.Context, commitDiffBranches []*ergo.StatusReport, tagName string) (string, error) {
+	if d.bodyTemplate == nil && !d.pullRequestNotes && !d.conventionalCommits {
+		return d.releaseBody(commitDiffBranches, d.releaseBodyPrefix, d.releaseBodyBranches), nil
+	}
+
+	model := &BodyModel{
+		Version:    tagName,
+		BaseBranch: d.baseBranch,
+		Prefix:     d.releaseBodyPrefix,
+	}
+	for _, diffBranch := range commitDiffBranches {
+		branchText, ok := d.releaseBodyBranches[diffBranch.Branch]
+		if !ok {
+			branchText = diffBranch.Branch
+		}
+		model.Branches = append(model.Branches, &BodyBranch{
+			Name:        diffBranch.Branch,
+			DisplayName: branchText,
+			Marker:      bodyMarker(branchText),
+		})
+	}
+	if len(commitDiffBranches) >= 1 {
+		model.Commits = commitDiffBranches[0].Behind
 	}
 
-	if !confirm {
-		return nil
+	if d.pullRequestNotes {
+		pulls, unmerged, err := d.mergedPullRequests(ctx, model.Commits)
+		if err != nil {
+			return "", err
+		}
+		model.PullRequests = pulls
+		model.Sections = pullRequestSections(pulls, unmerged, d.labelSections)
+	}
+
+	if d.conventionalCommits {
+		model.BreakingChanges, model.CommitSections = conventionalSections(model.Commits)
 	}
 
-	return d.host.CreateDraftRelease(ctx, releaseName, tagName, releaseBody, d.baseBranch)
+	if d.bodyTemplate == nil && d.pullRequestNotes {
+		return d.pullRequestsBody(model), nil
+	}
+	if d.bodyTemplate == nil {
+		return d.conventionalBody(model), nil
+	}
+
+	var body strings.Builder
+	if err := d.bodyTemplate.Execute(&body, model); err != nil {
+		return "", fmt.Errorf("error rendering release body template: %w", err)
+	}
+	return body.String(), nil
 }
 
 // releaseBody output needed for github release body.
@@ -84,8 +268,7 @@ func (d *Draft) releaseBody(commitDiffBranches []*ergo.StatusReport, releaseBody
 		if !ok {
 			branchText = diffBranch.Branch
 		}
-		formattedBranches = append(formattedBranches,
-			fmt.Sprintf("%s ![](https://img.shields.io/badge/released-No-red.svg)", branchText))
+		formattedBranches = append(formattedBranches, bodyMarker(branchText))
 	}
 
 	if len(commitDiffBranches) >= 1 {
@@ -1,8 +1,10 @@
 package release
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/beatlabs/ergo"
 	"github.com/beatlabs/ergo/cli"
@@ -108,3 +110,141 @@ func TestCreateCreateDraftReleaseShouldReturnError(t *testing.T) {
 		t.Error("expected create response to be nil.")
 	}
 }
+
+func TestCreateShouldRenderTheBodyTemplate(t *testing.T) {
+	date := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{
+				{
+					Branch: "release-gr",
+					Behind: []*ergo.Commit{
+						{SHA: "0123456789", Author: "alice", Date: date, Message: "Add login\n\nDetails"},
+					},
+				},
+				{Branch: "release-mx"},
+			}, nil
+		},
+	}
+
+	bodyTemplate, err := ParseBodyTemplate(
+		"{{ .Version }} from {{ .BaseBranch }}\n{{ .Markers }}\n" +
+			"{{ range .Commits }}* {{ shortSHA .SHA }} {{ firstLine .Message }} ({{ .Author }}, {{ .Date.Format \"2006-01-02\" }})\n{{ end }}" +
+			"{{ range .Branches }}{{ .Name }}={{ .DisplayName }};{{ end }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &mock.CLI{}
+	draft := NewDraft(c, host, "master", "", []string{"release-gr", "release-mx"}, map[string]string{"release-gr": ":greece:"})
+	draft.SetBodyTemplate(bodyTemplate)
+
+	if err = draft.Create(context.Background(), "", "1.2.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	want := "1.2.0 from master\n" +
+		":greece: ![](https://img.shields.io/badge/released-No-red.svg) release-mx ![](https://img.shields.io/badge/released-No-red.svg)\n" +
+		"* 0123456 Add login (alice, 2026-10-17)\n" +
+		"release-gr=:greece:;release-mx=release-mx;"
+	if len(c.PrintLines) != 1 || c.PrintLines[0] != want {
+		t.Errorf("got body %q; want %q", c.PrintLines, want)
+	}
+}
+
+func TestCreateShouldReturnErrorForFailingBodyTemplate(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Branch: "release-gr"}}, nil
+		},
+		CreateDraftReleaseFn: func() error {
+			t.Error("the draft should not be created")
+			return nil
+		},
+	}
+
+	bodyTemplate, err := ParseBodyTemplate("{{ .Unknown }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	draft := NewDraft(&mock.CLI{}, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetBodyTemplate(bodyTemplate)
+
+	if err = draft.Create(context.Background(), "", "1.2.0", true); err == nil {
+		t.Error("Create should return error when the template fails")
+	}
+}
+
+func TestParseBodyTemplateShouldReturnErrorForInvalidTemplate(t *testing.T) {
+	if _, err := ParseBodyTemplate("{{ .Version "); err == nil {
+		t.Error("ParseBodyTemplate should return error for an invalid template")
+	}
+}
+
+func TestCreateShouldPrintTheDraftReport(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Branch: "release-gr"}}, nil
+		},
+	}
+	c := &mock.CLI{}
+
+	if err := NewDraft(c, host, "master", "", []string{"release-gr"}, nil).Create(context.Background(), "name", "1.2.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	if len(c.PrintObjectCalls) != 1 {
+		t.Fatalf("expected one report, got %d", len(c.PrintObjectCalls))
+	}
+	report := c.PrintObjectCalls[0].(*DraftReport)
+	if report.Version != "1.2.0" || report.Name != "name" || report.Body != c.PrintLines[0] || !report.Created {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestCreateShouldFlagTruncatedDiff(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{
+				Branch:    "release-gr",
+				Behind:    []*ergo.Commit{{Message: "foo"}},
+				BehindBy:  300,
+				Truncated: true,
+			}}, nil
+		},
+	}
+	c := &mock.CLI{}
+
+	if err := NewDraft(c, host, "master", "", []string{"release-gr"}, nil).Create(context.Background(), "name", "1.2.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	report := c.PrintObjectCalls[0].(*DraftReport)
+	if !report.Truncated {
+		t.Errorf("expected the report to be truncated, got %+v", report)
+	}
+}
+
+func TestCreateShouldNotCreateTheDraftInDryRun(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Branch: "release-gr"}}, nil
+		},
+		CreateDraftReleaseFn: func() error {
+			return errors.New("the draft should not be created in a dry run")
+		},
+	}
+	c := &mock.CLI{}
+
+	draft := NewDraft(c, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetDryRun()
+	if err := draft.Create(context.Background(), "name", "1.2.0", false); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	report := c.PrintObjectCalls[0].(*DraftReport)
+	if report.Created || !report.DryRun || c.ConfirmationCalls != 0 {
+		t.Errorf("unexpected report %+v after %d confirmations", report, c.ConfirmationCalls)
+	}
+}
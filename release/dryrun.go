@@ -0,0 +1,121 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+)
+
+// DryRunCall is a mutating call of the host which a dry run recorded instead of making it.
+type DryRunCall struct {
+	Method      string `json:"method" yaml:"method"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// DryRunHost serves the reads from the wrapped host and records the mutating calls instead of
+// making them, so that a flow can be rehearsed against the real repository.
+type DryRunHost struct {
+	ergo.Host
+	c ergo.CLI
+
+	mu    sync.Mutex
+	calls []DryRunCall
+	// drafts are the draft releases recorded, newest first, listed with the releases of the host.
+	drafts []*ergo.Release
+}
+
+// NewDryRunHost wraps the host, printing every mutating call it records.
+func NewDryRunHost(host ergo.Host, c ergo.CLI) *DryRunHost {
+	return &DryRunHost{Host: host, c: c}
+}
+
+// Calls returns the mutating calls recorded, in order.
+func (h *DryRunHost) Calls() []DryRunCall {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]DryRunCall(nil), h.calls...)
+}
+
+// CreateDraftRelease records the creation of the draft release, which is then listed by
+// ListReleases with a negative ID.
+func (h *DryRunHost) CreateDraftRelease(ctx context.Context, name, tagName, releaseBody, targetBranch string) error {
+	h.record("CreateDraftRelease", "create draft release %q with tag %s on %s", name, tagName, targetBranch)
+	h.mu.Lock()
+	draft := &ergo.Release{ID: -int64(len(h.drafts) + 1), TagName: tagName, Body: releaseBody, Draft: true}
+	h.drafts = append([]*ergo.Release{draft}, h.drafts...)
+	h.mu.Unlock()
+	return nil
+}
+
+// ListReleases returns the draft releases recorded followed by the releases of the host.
+func (h *DryRunHost) ListReleases(ctx context.Context) ([]*ergo.Release, error) {
+	releases, err := h.Host.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append(append([]*ergo.Release(nil), h.drafts...), releases...), nil
+}
+
+// EditRelease records the edit of the release and returns it unchanged.
+func (h *DryRunHost) EditRelease(ctx context.Context, release *ergo.Release) (*ergo.Release, error) {
+	if release == nil {
+		return nil, errors.New("nothing to release: input release is nil")
+	}
+	h.record("EditRelease", "edit the body of release %s (ID=%d)", release.TagName, release.ID)
+	return release, nil
+}
+
+// PublishRelease records the publication of the release.
+func (h *DryRunHost) PublishRelease(ctx context.Context, releaseID int64) error {
+	h.mu.Lock()
+	var draft *ergo.Release
+	for _, d := range h.drafts {
+		if d.ID == releaseID {
+			draft = d
+		}
+	}
+	h.mu.Unlock()
+	if draft != nil {
+		h.record("PublishRelease", "publish the draft release %s", draft.TagName)
+		return nil
+	}
+	h.record("PublishRelease", "publish release (ID=%d)", releaseID)
+	return nil
+}
+
+// CreateTag records the creation of the tag and returns it.
+func (h *DryRunHost) CreateTag(ctx context.Context, versionName, sha, m string) (*ergo.Tag, error) {
+	h.record("CreateTag", "create tag %s on %s", versionName, sha)
+	return &ergo.Tag{Name: versionName}, nil
+}
+
+// UpdateBranchFromTag records the update of the branch to the tag.
+func (h *DryRunHost) UpdateBranchFromTag(ctx context.Context, tag, toBranch string, force bool) error {
+	if force {
+		h.record("UpdateBranchFromTag", "force update %s to %s", toBranch, tag)
+		return nil
+	}
+	h.record("UpdateBranchFromTag", "update %s to %s", toBranch, tag)
+	return nil
+}
+
+// UpdateBranchToSHA records the update of the branch to the commit.
+func (h *DryRunHost) UpdateBranchToSHA(ctx context.Context, sha, toBranch string, force bool) error {
+	h.record("UpdateBranchToSHA", "update %s to %s", toBranch, sha)
+	return nil
+}
+
+// record prints and records the call.
+func (h *DryRunHost) record(method, format string, args ...interface{}) {
+	description := fmt.Sprintf(format, args...)
+	h.mu.Lock()
+	h.calls = append(h.calls, DryRunCall{Method: method, Description: description})
+	h.mu.Unlock()
+	h.c.PrintColorizedLine("DRY RUN: ", "would "+description, cli.WarningType)
+}
@@ -0,0 +1,60 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestDryRunHostShouldRecordTheMutatingCalls(t *testing.T) {
+	fail := errors.New("the host should not be called")
+	host := &mock.RepositoryClient{
+		CreateDraftReleaseFn:  func() error { return fail },
+		EditReleaseFn:         func() (*ergo.Release, error) { return nil, fail },
+		PublishReleaseFn:      func(ctx context.Context, releaseID int64) error { return fail },
+		CreateTagFn:           func() (*ergo.Tag, error) { return nil, fail },
+		UpdateBranchFromTagFn: func() error { return fail },
+		UpdateBranchToSHAFn:   func() error { return fail },
+		GetRepoNameFn:         func() string { return "beatlabs/ergo" },
+	}
+	dryRunHost := NewDryRunHost(host, &mock.CLI{})
+
+	if err := dryRunHost.CreateDraftRelease(ctx, "name", "1.2.0", "body", "master"); err != nil {
+		t.Error(err)
+	}
+	if _, err := dryRunHost.EditRelease(ctx, &ergo.Release{ID: 1, TagName: "1.2.0"}); err != nil {
+		t.Error(err)
+	}
+	if err := dryRunHost.PublishRelease(ctx, 1); err != nil {
+		t.Error(err)
+	}
+	if tag, err := dryRunHost.CreateTag(ctx, "1.2.0", "sha", ""); err != nil || tag.Name != "1.2.0" {
+		t.Errorf("expected the tag, got %v, %v", tag, err)
+	}
+	if err := dryRunHost.UpdateBranchFromTag(ctx, "1.2.0", "release-gr", true); err != nil {
+		t.Error(err)
+	}
+	if err := dryRunHost.UpdateBranchToSHA(ctx, "sha", "release-gr", true); err != nil {
+		t.Error(err)
+	}
+	if got := dryRunHost.GetRepoName(); got != "beatlabs/ergo" {
+		t.Errorf("expected the reads to be served by the host, got %q", got)
+	}
+
+	want := []string{"CreateDraftRelease", "EditRelease", "PublishRelease", "CreateTag", "UpdateBranchFromTag", "UpdateBranchToSHA"}
+	calls := dryRunHost.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %+v", len(want), calls)
+	}
+	for i, method := range want {
+		if calls[i].Method != method {
+			t.Errorf("expected call %d to be %s, got %+v", i, method, calls[i])
+		}
+	}
+	if calls[4].Description != "force update release-gr to 1.2.0" {
+		t.Errorf("unexpected description %q", calls[4].Description)
+	}
+}
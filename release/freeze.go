@@ -0,0 +1,506 @@
+package release
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+)
+
+// FreezeWindow is a period in which no release branch may be deployed.
+type FreezeWindow struct {
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
+
+// WeeklyFreeze is a freeze window recurring every week, from the start day and time to the end
+// day and time, which may be in the next week such as from Friday to Monday.
+type WeeklyFreeze struct {
+	StartDay time.Weekday
+	Start    time.Duration
+	EndDay   time.Weekday
+	End      time.Duration
+	Reason   string
+}
+
+// FreezeCalendar is the periods in which the release branches may not be deployed. The weekly
+// windows are in the location.
+type FreezeCalendar struct {
+	Windows  []FreezeWindow
+	Weekly   []WeeklyFreeze
+	Location *time.Location
+}
+
+// Freeze enforces the freeze calendar, or the calendar of a branch which replaces it, on the
+// planned start times of the release branches. A frozen plan is blocked unless the freeze is
+// overridden with a reason, which is recorded in the release body.
+type Freeze struct {
+	calendar       *FreezeCalendar
+	branches       map[string]*FreezeCalendar
+	overrideReason string
+}
+
+// NewFreeze initialize and return a new Freeze object. The branches are matched regardless of
+// their case, since the keys of the config are lowercased.
+func NewFreeze(calendar *FreezeCalendar, branches map[string]*FreezeCalendar) *Freeze {
+	f := &Freeze{calendar: calendar, branches: make(map[string]*FreezeCalendar, len(branches))}
+	for branch, c := range branches {
+		f.branches[strings.ToLower(branch)] = c
+	}
+	return f
+}
+
+// SetOverride lets a frozen plan proceed, the reason being recorded in the release body.
+func (f *Freeze) SetOverride(reason string) {
+	f.overrideReason = reason
+}
+
+// Check prints the release branches whose start time falls in a freeze window, and the windows
+// starting between the first and the last start time of the branches following the same calendar,
+// and returns an error if there is any, unless the freeze is overridden, in which case it reports
+// whether the override was needed.
+func (f *Freeze) Check(c ergo.CLI, releaseBranches []string, startTimes []time.Time) (bool, error) {
+	frozen := 0
+	reported := make(map[FreezeWindow]bool)
+	var groups []*freezeGroup
+	for i, branch := range releaseBranches {
+		calendar := f.calendarOf(branch)
+		groups = addToFreezeGroup(groups, calendar, startTimes[i])
+		window, ok := calendar.window(startTimes[i])
+		if !ok {
+			continue
+		}
+		frozen++
+		reported[window] = true
+		message := fmt.Sprintf("%s at %s is in the freeze window from %s to %s",
+			branch, startTimes[i].Format(freezeTimeLayout), window.Start.Format(freezeTimeLayout), window.End.Format(freezeTimeLayout))
+		c.PrintColorizedLine("FROZEN: ", message+freezeReason(window), cli.ErrorType)
+	}
+
+	crossed := 0
+	for _, group := range groups {
+		for _, window := range group.calendar.windowsBetween(group.first, group.last) {
+			if reported[window] {
+				continue
+			}
+			reported[window] = true
+			crossed++
+			message := fmt.Sprintf("the release branches from %s to %s cross the freeze window from %s to %s",
+				group.first.Format(freezeTimeLayout), group.last.Format(freezeTimeLayout), window.Start.Format(freezeTimeLayout), window.End.Format(freezeTimeLayout))
+			c.PrintColorizedLine("FROZEN: ", message+freezeReason(window), cli.ErrorType)
+		}
+	}
+
+	switch {
+	case frozen == 0 && crossed == 0:
+		return false, nil
+	case f.overrideReason == "" && frozen == 0:
+		return false, fmt.Errorf("the release branches are planned across %d freeze windows, run again with --override-freeze <reason> to proceed", crossed)
+	case f.overrideReason == "":
+		return false, fmt.Errorf("%d release branches are planned in a freeze window, run again with --override-freeze <reason> to proceed", frozen)
+	}
+	c.PrintColorizedLine("OVERRIDE: ", "the freeze is overridden: "+f.overrideReason, cli.WarningType)
+	return true, nil
+}
+
+// calendarOf returns the freeze calendar of the branch, or the freeze calendar if it has none.
+func (f *Freeze) calendarOf(branch string) *FreezeCalendar {
+	if calendar, ok := f.branches[strings.ToLower(branch)]; ok {
+		return calendar
+	}
+	return f.calendar
+}
+
+// freezeGroup is the first and the last start time of the release branches following a calendar.
+type freezeGroup struct {
+	calendar    *FreezeCalendar
+	first, last time.Time
+}
+
+// addToFreezeGroup adds the start time to the group of the calendar, in the order of the calendars.
+func addToFreezeGroup(groups []*freezeGroup, calendar *FreezeCalendar, startTime time.Time) []*freezeGroup {
+	for _, group := range groups {
+		if group.calendar != calendar {
+			continue
+		}
+		if startTime.Before(group.first) {
+			group.first = startTime
+		}
+		if startTime.After(group.last) {
+			group.last = startTime
+		}
+		return groups
+	}
+	return append(groups, &freezeGroup{calendar: calendar, first: startTime, last: startTime})
+}
+
+// freezeReason returns the reason of the freeze window in parentheses, if any.
+func freezeReason(window FreezeWindow) string {
+	if window.Reason == "" {
+		return ""
+	}
+	return " (" + window.Reason + ")"
+}
+
+// RecordOverride returns the release body with a note of the freeze override by the command.
+func (f *Freeze) RecordOverride(body, command string, t time.Time) string {
+	return fmt.Sprintf("%s\n\n> Deploy freeze overridden by %s on %s: %s",
+		strings.TrimRight(body, "\n"), command, t.Format(freezeTimeLayout), f.overrideReason)
+}
+
+// freezeTimeLayout is the layout of the times of the freeze messages.
+const freezeTimeLayout = "Mon 02 Jan 2006 15:04 MST"
+
+// week is the duration of a week.
+const week = 7 * 24 * time.Hour
+
+// window returns the freeze window the time falls in, if any. A nil calendar has none.
+func (c *FreezeCalendar) window(t time.Time) (FreezeWindow, bool) {
+	if c == nil {
+		return FreezeWindow{}, false
+	}
+	for _, w := range c.Windows {
+		if !t.Before(w.Start) && t.Before(w.End) {
+			return w, true
+		}
+	}
+
+	loc := c.location()
+	// The offsets in the week are read from the wall clock, so that the windows keep their hours
+	// in the weeks the daylight saving time starts or ends.
+	t = t.In(loc)
+	now := time.Duration(t.Weekday())*24*time.Hour + time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	weekStart := t.Day() - int(t.Weekday())
+	for _, w := range c.Weekly {
+		start := time.Duration(w.StartDay)*24*time.Hour + w.Start
+		if (now-start+week)%week >= w.length() {
+			continue
+		}
+		startDay := weekStart + int(w.StartDay)
+		if now < start {
+			startDay -= 7
+		}
+		return w.occurrence(t, startDay, loc), true
+	}
+	return FreezeWindow{}, false
+}
+
+// windowsBetween returns the freeze windows overlapping the period from the first to the last
+// time, including the occurrences of the weekly windows. A nil calendar has none.
+func (c *FreezeCalendar) windowsBetween(first, last time.Time) []FreezeWindow {
+	if c == nil {
+		return nil
+	}
+	var windows []FreezeWindow
+	for _, w := range c.Windows {
+		if !w.Start.After(last) && w.End.After(first) {
+			windows = append(windows, w)
+		}
+	}
+
+	loc := c.location()
+	base := first.In(loc)
+	for _, w := range c.Weekly {
+		// an occurrence starting in the week before the first time may last until after it
+		for day := base.Day() - 7; !wallClock(base, day, 0, loc).After(last); day++ {
+			if wallClock(base, day, 0, loc).Weekday() != w.StartDay {
+				continue
+			}
+			if window := w.occurrence(base, day, loc); !window.Start.After(last) && window.End.After(first) {
+				windows = append(windows, window)
+			}
+		}
+	}
+	return windows
+}
+
+// location returns the location of the weekly windows, the local one by default.
+func (c *FreezeCalendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+// length returns the duration of the weekly window.
+func (w WeeklyFreeze) length() time.Duration {
+	start := time.Duration(w.StartDay)*24*time.Hour + w.Start
+	end := time.Duration(w.EndDay)*24*time.Hour + w.End
+	return (end - start + week) % week
+}
+
+// occurrence returns the weekly window starting on the day of the month of t.
+func (w WeeklyFreeze) occurrence(t time.Time, day int, loc *time.Location) FreezeWindow {
+	return FreezeWindow{
+		Start:  wallClock(t, day, w.Start, loc),
+		End:    wallClock(t, day, w.Start+w.length(), loc),
+		Reason: w.Reason,
+	}
+}
+
+// wallClock returns the time at the wall clock offset from the midnight of the day of the month
+// of t, the day overflowing into the next or previous months.
+func wallClock(t time.Time, day int, offset time.Duration, loc *time.Location) time.Time {
+	day += int(offset / (24 * time.Hour))
+	offset %= 24 * time.Hour
+	return time.Date(t.Year(), t.Month(), day,
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second), 0, loc)
+}
+
+// ParseICal returns the events of the iCal calendar as freeze windows, their summary being the
+// reason. The times without a zone are in the location, and the events lasting whole days end
+// the day after they start if they have no end. The events repeated every week or year are
+// expanded to their occurrences, up to the until time if their rule has no end.
+func ParseICal(r io.Reader, loc *time.Location, until time.Time) ([]FreezeWindow, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		windows []FreezeWindow
+		event   map[string]string
+		params  map[string]string
+	)
+	for _, line := range lines {
+		switch line {
+		case "BEGIN:VEVENT":
+			event, params = make(map[string]string), make(map[string]string)
+			continue
+		case "END:VEVENT":
+			window, err := icalWindow(event, params, loc)
+			if err != nil {
+				return nil, err
+			}
+			if event["RRULE"] == "" {
+				windows = append(windows, window)
+			} else {
+				occurrences, err := icalOccurrences(window, event["RRULE"], loc, until)
+				if err != nil {
+					return nil, fmt.Errorf("iCal event %q: %w", window.Reason, err)
+				}
+				windows = append(windows, occurrences...)
+			}
+			event = nil
+			continue
+		}
+		if event == nil {
+			continue
+		}
+		nameAndParams, value, ok := cutICal(line, ":")
+		if !ok {
+			continue
+		}
+		name, param, _ := cutICal(nameAndParams, ";")
+		name = strings.ToUpper(name)
+		event[name] = value
+		params[name] = param
+	}
+	return windows, nil
+}
+
+// unfoldICal returns the lines of the iCal calendar, joining the lines folded with a leading
+// space or tab.
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading iCal calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// icalWindow returns the freeze window of the properties of an iCal event.
+func icalWindow(event, params map[string]string, loc *time.Location) (FreezeWindow, error) {
+	summary := strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(event["SUMMARY"])
+	if event["DTSTART"] == "" {
+		return FreezeWindow{}, fmt.Errorf("iCal event %q has no start", summary)
+	}
+	start, allDay, err := icalTime(event["DTSTART"], params["DTSTART"], loc)
+	if err != nil {
+		return FreezeWindow{}, fmt.Errorf("iCal event %q: %w", summary, err)
+	}
+
+	var end time.Time
+	switch {
+	case event["DTEND"] != "":
+		if end, _, err = icalTime(event["DTEND"], params["DTEND"], loc); err != nil {
+			return FreezeWindow{}, fmt.Errorf("iCal event %q: %w", summary, err)
+		}
+	case allDay:
+		end = start.AddDate(0, 0, 1)
+	default:
+		return FreezeWindow{}, fmt.Errorf("iCal event %q has no end", summary)
+	}
+	if !end.After(start) {
+		return FreezeWindow{}, fmt.Errorf("iCal event %q ends before it starts", summary)
+	}
+	return FreezeWindow{Start: start, End: end, Reason: summary}, nil
+}
+
+// icalOccurrences returns the occurrences of the freeze window repeated by the iCal recurrence
+// rule, up to the until time if the rule has neither a count nor an end. Only the weekly rules,
+// on the days of the week of the event or of BYDAY, and the yearly rules are supported.
+func icalOccurrences(window FreezeWindow, rule string, loc *time.Location, until time.Time) ([]FreezeWindow, error) {
+	var (
+		freq            string
+		interval, count = 1, 0
+		byDay           []time.Weekday
+		weekStart       = time.Monday
+	)
+	for _, part := range strings.Split(rule, ";") {
+		name, value, _ := cutICal(part, "=")
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			freq = strings.ToUpper(value)
+			if freq != "YEARLY" && freq != "WEEKLY" {
+				return nil, fmt.Errorf("the %s recurrence is not supported", strings.ToLower(value))
+			}
+		case "INTERVAL", "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence %s %q", strings.ToLower(name), value)
+			}
+			if strings.EqualFold(name, "INTERVAL") {
+				interval = n
+			} else {
+				count = n
+			}
+		case "UNTIL":
+			t, _, err := icalTime(value, "", loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recurrence end: %w", err)
+			}
+			until = t
+		case "BYDAY", "WKST":
+			days, err := icalWeekdays(value)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(name, "BYDAY") {
+				byDay = days
+			} else if len(days) == 1 {
+				weekStart = days[0]
+			}
+		default:
+			return nil, fmt.Errorf("the recurrence rule %s is not supported", name)
+		}
+	}
+
+	var windows []FreezeWindow
+	// add adds the occurrence, reporting false once the rule has ended
+	add := func(days, years int) bool {
+		start := window.Start.AddDate(years, 0, days)
+		if len(windows) > 0 && (count > 0 && len(windows) >= count || count == 0 && start.After(until)) {
+			return false
+		}
+		windows = append(windows, FreezeWindow{Start: start, End: window.End.AddDate(years, 0, days), Reason: window.Reason})
+		return true
+	}
+
+	switch freq {
+	case "":
+		return nil, fmt.Errorf("the recurrence %q has no frequency", rule)
+	case "YEARLY":
+		if byDay != nil {
+			return nil, errors.New("the recurrence rule BYDAY is only supported weekly")
+		}
+		for i := 0; ; i++ {
+			if !add(0, i*interval) {
+				return windows, nil
+			}
+		}
+	}
+
+	if byDay == nil {
+		byDay = []time.Weekday{window.Start.Weekday()}
+	}
+	// the days are offset from the start of the week, which is not always a Sunday
+	offsets := make([]int, 0, len(byDay))
+	for _, day := range byDay {
+		offsets = append(offsets, (int(day)-int(weekStart)+7)%7)
+	}
+	sort.Ints(offsets)
+	first := (int(window.Start.Weekday()) - int(weekStart) + 7) % 7
+	for i := 0; ; i++ {
+		for _, offset := range offsets {
+			days := 7*i*interval + offset - first
+			if days < 0 {
+				continue
+			}
+			if !add(days, 0) {
+				return windows, nil
+			}
+		}
+	}
+}
+
+// icalWeekdays parses the comma separated iCal days of the week, such as MO,WE. The days of the
+// month, such as 1MO, are not supported.
+func icalWeekdays(value string) ([]time.Weekday, error) {
+	weekdays := map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		day, ok := weekdays[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("the recurrence day %q is not supported", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// icalTime parses an iCal date or date-time, in the zone of its TZID parameter, if any, and
+// reports whether it is a date.
+func icalTime(value, params string, loc *time.Location) (time.Time, bool, error) {
+	for _, param := range strings.Split(params, ";") {
+		if name, tzid, ok := cutICal(param, "="); ok && strings.EqualFold(name, "TZID") {
+			tzLoc, err := time.LoadLocation(strings.Trim(tzid, `"`))
+			if err != nil {
+				return time.Time{}, false, err
+			}
+			loc = tzLoc
+		}
+	}
+
+	switch {
+	case len(value) == len("20060102"):
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	case value != "":
+		t, err := time.ParseInLocation("20060102T150405", value, loc)
+		return t, false, err
+	}
+	return time.Time{}, false, errors.New("empty time")
+}
+
+// cutICal slices the text around the first separator, reporting whether it was found.
+func cutICal(text, sep string) (before, after string, found bool) {
+	if i := strings.Index(text, sep); i >= 0 {
+		return text[:i], text[i+len(sep):], true
+	}
+	return text, "", false
+}
@@ -0,0 +1,370 @@
+package release
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestFreezeCalendarShouldMatchTheWeeklyWindows(t *testing.T) {
+	calendar := &FreezeCalendar{
+		Weekly: []WeeklyFreeze{
+			{StartDay: time.Friday, Start: 14 * time.Hour, EndDay: time.Monday, End: 8 * time.Hour, Reason: "weekend"},
+			{StartDay: time.Wednesday, Start: 12 * time.Hour, EndDay: time.Wednesday, End: 13 * time.Hour, Reason: "lunch"},
+		},
+		Location: time.UTC,
+	}
+	tests := map[string]struct {
+		t          time.Time
+		wantReason string
+	}{
+		"thursday":         {t: time.Date(2026, 10, 22, 15, 0, 0, 0, time.UTC)},
+		"friday morning":   {t: time.Date(2026, 10, 23, 13, 59, 0, 0, time.UTC)},
+		"friday afternoon": {t: time.Date(2026, 10, 23, 14, 0, 0, 0, time.UTC), wantReason: "weekend"},
+		"sunday":           {t: time.Date(2026, 10, 25, 10, 0, 0, 0, time.UTC), wantReason: "weekend"},
+		"monday early":     {t: time.Date(2026, 10, 26, 7, 59, 0, 0, time.UTC), wantReason: "weekend"},
+		"monday":           {t: time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC)},
+		"wednesday lunch":  {t: time.Date(2026, 10, 21, 12, 30, 0, 0, time.UTC), wantReason: "lunch"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			window, ok := calendar.window(tt.t)
+			if ok != (tt.wantReason != "") || window.Reason != tt.wantReason {
+				t.Errorf("expected the window %q, got %+v", tt.wantReason, window)
+			}
+		})
+	}
+
+	window, _ := calendar.window(time.Date(2026, 10, 25, 10, 0, 0, 0, time.UTC))
+	if !window.Start.Equal(time.Date(2026, 10, 23, 14, 0, 0, 0, time.UTC)) || !window.End.Equal(time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected window from %v to %v", window.Start, window.End)
+	}
+}
+
+func TestFreezeCalendarShouldKeepTheWeeklyHoursAcrossDaylightSavingTime(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// the clocks go back from 04:00 to 03:00 on Sunday 25 October 2026 in Athens
+	calendar := &FreezeCalendar{
+		Weekly:   []WeeklyFreeze{{StartDay: time.Friday, Start: 14 * time.Hour, EndDay: time.Monday, End: 8 * time.Hour, Reason: "weekend"}},
+		Location: athens,
+	}
+	tests := map[string]struct {
+		t    time.Time
+		want bool
+	}{
+		"friday before":     {t: time.Date(2026, 10, 23, 13, 30, 0, 0, athens)},
+		"friday":            {t: time.Date(2026, 10, 23, 14, 30, 0, 0, athens), want: true},
+		"monday early":      {t: time.Date(2026, 10, 26, 7, 30, 0, 0, athens), want: true},
+		"monday":            {t: time.Date(2026, 10, 26, 8, 30, 0, 0, athens)},
+		"spring friday":     {t: time.Date(2026, 3, 27, 13, 30, 0, 0, athens)},
+		"spring monday":     {t: time.Date(2026, 3, 30, 7, 30, 0, 0, athens), want: true},
+		"spring monday end": {t: time.Date(2026, 3, 30, 8, 0, 0, 0, athens)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := calendar.window(tt.t); ok != tt.want {
+				t.Errorf("expected the time to be frozen: %t, got %t", tt.want, ok)
+			}
+		})
+	}
+
+	window, _ := calendar.window(time.Date(2026, 10, 26, 7, 30, 0, 0, athens))
+	if !window.Start.Equal(time.Date(2026, 10, 23, 14, 0, 0, 0, athens)) || !window.End.Equal(time.Date(2026, 10, 26, 8, 0, 0, 0, athens)) {
+		t.Errorf("unexpected window from %v to %v", window.Start, window.End)
+	}
+}
+
+func TestFreezeCheckShouldPreferTheCalendarOfTheBranch(t *testing.T) {
+	now := time.Date(2026, 12, 25, 10, 0, 0, 0, time.UTC)
+	christmas := &FreezeCalendar{Windows: []FreezeWindow{{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Reason: "Christmas"}}}
+	freeze := NewFreeze(christmas, map[string]*FreezeCalendar{"release-jp": {}})
+
+	_, err := freeze.Check(&mock.CLI{}, []string{"release-gr", "release-jp"}, []time.Time{now, now})
+	if err == nil || !strings.HasPrefix(err.Error(), "1 release branches") {
+		t.Errorf("expected only release-gr to be frozen, got %v", err)
+	}
+	if _, err = freeze.Check(&mock.CLI{}, []string{"release-jp", "Release-JP"}, []time.Time{now, now}); err != nil {
+		t.Errorf("expected release-jp not to be frozen, got %v", err)
+	}
+
+	// only the branches following the freeze calendar can cross its windows
+	later := []time.Time{now.Add(-2 * time.Hour), now.Add(2 * time.Hour)}
+	if _, err = freeze.Check(&mock.CLI{}, []string{"release-gr", "release-jp"}, later); err != nil {
+		t.Errorf("expected release-jp not to cross the window, got %v", err)
+	}
+	_, err = freeze.Check(&mock.CLI{}, []string{"release-gr", "release-mx"}, later)
+	if err == nil || !strings.Contains(err.Error(), "across 1 freeze windows") {
+		t.Errorf("expected the schedule to cross the window, got %v", err)
+	}
+}
+
+func TestParseICalShouldReturnTheEventsAsFreezeWindows(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	const calendar = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Black Friday\\, Cyber Monday\r\n" +
+		"DTSTART;VALUE=DATE:20261127\r\n" +
+		"DTEND;VALUE=DATE:20261201\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Christmas\r\n" +
+		"DTSTART;VALUE=DATE:20261225\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Data center\r\n" +
+		"  migration\r\n" +
+		"DTSTART:20261110T220000Z\r\n" +
+		"DTEND;TZID=Europe/Athens:20261111T060000\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	windows, err := ParseICal(strings.NewReader(calendar), athens, time.Time{})
+	if err != nil {
+		t.Fatalf("ParseICal should not return the error: %v", err)
+	}
+	want := []FreezeWindow{
+		{Start: time.Date(2026, 11, 27, 0, 0, 0, 0, athens), End: time.Date(2026, 12, 1, 0, 0, 0, 0, athens), Reason: "Black Friday, Cyber Monday"},
+		{Start: time.Date(2026, 12, 25, 0, 0, 0, 0, athens), End: time.Date(2026, 12, 26, 0, 0, 0, 0, athens), Reason: "Christmas"},
+		{Start: time.Date(2026, 11, 10, 22, 0, 0, 0, time.UTC), End: time.Date(2026, 11, 11, 6, 0, 0, 0, athens), Reason: "Data center migration"},
+	}
+	if len(windows) != len(want) {
+		t.Fatalf("expected %d windows, got %+v", len(want), windows)
+	}
+	for i, w := range want {
+		if !windows[i].Start.Equal(w.Start) || !windows[i].End.Equal(w.End) || windows[i].Reason != w.Reason {
+			t.Errorf("expected the window %+v, got %+v", w, windows[i])
+		}
+	}
+
+	_, err = ParseICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Broken\nDTSTART:20261110T220000\nEND:VEVENT\n"), athens, time.Time{})
+	if err == nil {
+		t.Error("expected ParseICal to reject an event without an end")
+	}
+}
+
+func TestParseICalShouldExpandTheRecurringEvents(t *testing.T) {
+	const calendar = "BEGIN:VEVENT\n" +
+		"SUMMARY:Christmas\n" +
+		"DTSTART;VALUE=DATE:20241224\n" +
+		"DTEND;VALUE=DATE:20241227\n" +
+		"RRULE:FREQ=YEARLY\n" +
+		"END:VEVENT\n" +
+		"BEGIN:VEVENT\n" +
+		"SUMMARY:Sprint demo\n" +
+		"DTSTART:20261102T150000\n" +
+		"DTEND:20261102T160000\n" +
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=3\n" +
+		"END:VEVENT\n" +
+		"BEGIN:VEVENT\n" +
+		"SUMMARY:Quarter close\n" +
+		"DTSTART;VALUE=DATE:20260330\n" +
+		"RRULE:FREQ=YEARLY;UNTIL=20270101\n" +
+		"END:VEVENT\n" +
+		"BEGIN:VEVENT\n" +
+		"SUMMARY:Maintenance\n" +
+		"DTSTART:20261103T200000\n" +
+		"DTEND:20261103T220000\n" +
+		"RRULE:FREQ=WEEKLY;WKST=SU;BYDAY=TU,TH;COUNT=4\n" +
+		"END:VEVENT\n"
+
+	windows, err := ParseICal(strings.NewReader(calendar), time.UTC, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ParseICal should not return the error: %v", err)
+	}
+	var starts []string
+	for _, w := range windows {
+		starts = append(starts, w.Start.Format("2006-01-02 15:04")+" "+w.Reason)
+	}
+	want := []string{
+		"2024-12-24 00:00 Christmas",
+		"2025-12-24 00:00 Christmas",
+		"2026-12-24 00:00 Christmas",
+		"2026-11-02 15:00 Sprint demo",
+		"2026-11-16 15:00 Sprint demo",
+		"2026-11-30 15:00 Sprint demo",
+		"2026-03-30 00:00 Quarter close",
+		"2026-11-03 20:00 Maintenance",
+		"2026-11-05 20:00 Maintenance",
+		"2026-11-10 20:00 Maintenance",
+		"2026-11-12 20:00 Maintenance",
+	}
+	if strings.Join(starts, ", ") != strings.Join(want, ", ") {
+		t.Errorf("expected the occurrences %v, got %v", want, starts)
+	}
+	if !windows[2].End.Equal(time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the occurrence to last as long as the event, got %+v", windows[2])
+	}
+
+	_, err = ParseICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Payday\nDTSTART;VALUE=DATE:20261130\nRRULE:FREQ=MONTHLY\nEND:VEVENT\n"), time.UTC, time.Time{})
+	if err == nil || !strings.Contains(err.Error(), "monthly") {
+		t.Errorf("expected the monthly recurrence to be rejected, got %v", err)
+	}
+	_, err = ParseICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Thanksgiving\nDTSTART;VALUE=DATE:20261126\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH\nEND:VEVENT\n"), time.UTC, time.Time{})
+	if err == nil {
+		t.Error("expected the days of the month to be rejected")
+	}
+}
+
+func TestFreezeCheckShouldFindTheCrossedWeeklyWindows(t *testing.T) {
+	// Friday 18:00 to Monday 08:00
+	weekend := WeeklyFreeze{StartDay: time.Friday, Start: 18 * time.Hour, EndDay: time.Monday, End: 8 * time.Hour, Reason: "weekend"}
+	friday := time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 10, 26, 10, 0, 0, 0, time.UTC)
+	shared := &FreezeCalendar{Weekly: []WeeklyFreeze{weekend}, Location: time.UTC}
+	tests := map[string]struct {
+		calendar   *FreezeCalendar
+		branches   map[string]*FreezeCalendar
+		startTimes []time.Time
+		wantErr    string
+	}{
+		"crossed": {
+			calendar:   &FreezeCalendar{Weekly: []WeeklyFreeze{weekend}, Location: time.UTC},
+			startTimes: []time.Time{friday, monday},
+			wantErr:    "across 1 freeze windows",
+		},
+		"frozen": {
+			calendar:   &FreezeCalendar{Weekly: []WeeklyFreeze{weekend}, Location: time.UTC},
+			startTimes: []time.Time{friday.Add(7 * time.Hour), monday},
+			wantErr:    "1 release branches",
+		},
+		"before": {
+			calendar:   &FreezeCalendar{Weekly: []WeeklyFreeze{weekend}, Location: time.UTC},
+			startTimes: []time.Time{friday, friday.Add(time.Hour)},
+		},
+		"crossed by the branches of a calendar": {
+			calendar:   &FreezeCalendar{},
+			branches:   map[string]*FreezeCalendar{"release-gr": shared, "release-mx": shared},
+			startTimes: []time.Time{friday, monday},
+			wantErr:    "across 1 freeze windows",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			freeze := NewFreeze(test.calendar, test.branches)
+			_, err := freeze.Check(&mock.CLI{}, []string{"release-gr", "release-mx"}, test.startTimes)
+			if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("expected the error %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateShouldNotPublishAFrozenDraft(t *testing.T) {
+	now := time.Date(2026, 11, 27, 11, 0, 0, 0, time.UTC)
+	calendar := &FreezeCalendar{Windows: []FreezeWindow{{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Reason: "Black Friday"}}}
+	tests := map[string]struct {
+		override string
+		wantErr  bool
+	}{
+		"frozen":     {wantErr: true},
+		"overridden": {override: "security fix"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created, published bool
+			host := &mock.RepositoryClient{
+				DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+					return []*ergo.StatusReport{{Branch: "release-gr", Behind: []*ergo.Commit{{Message: "fix"}}}}, nil
+				},
+				CreateDraftReleaseFn: func() error {
+					created = true
+					return nil
+				},
+				ListReleasesFn: func() ([]*ergo.Release, error) {
+					return []*ergo.Release{{ID: 7, TagName: "1.1.0", Draft: true}, {ID: 6, TagName: "1.0.0"}}, nil
+				},
+				PublishReleaseFn: func(ctx context.Context, releaseID int64) error {
+					published = releaseID == 7
+					return nil
+				},
+			}
+			c := &mock.CLI{}
+			freeze := NewFreeze(calendar, nil)
+			freeze.SetOverride(tt.override)
+			draft := NewDraft(c, host, "develop", "", []string{"release-gr"}, nil)
+			draft.time = mock.NewMockedTime(now)
+			draft.SetPublish(freeze)
+
+			err := draft.Create(ctx, "1.1.0", "1.1.0", true)
+			if tt.wantErr {
+				if err == nil || created {
+					t.Errorf("expected the draft not to be created, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create should not return the error: %v", err)
+			}
+			if !published {
+				t.Error("expected the draft to be published")
+			}
+			report := c.PrintObjectCalls[0].(*DraftReport)
+			if !report.Published || !strings.Contains(report.Body, "overridden by draft --publish") || !strings.Contains(report.Body, tt.override) {
+				t.Errorf("unexpected report %+v", report)
+			}
+		})
+	}
+}
+
+func TestCreateShouldRecordThePublicationOfTheDraftInADryRun(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Branch: "release-gr", Behind: []*ergo.Commit{{Message: "fix"}}}}, nil
+		},
+		ListReleasesFn: func() ([]*ergo.Release, error) {
+			return []*ergo.Release{{ID: 6, TagName: "1.0.0"}}, nil
+		},
+		PublishReleaseFn: func(ctx context.Context, releaseID int64) error {
+			t.Errorf("expected no release to be published, got %d", releaseID)
+			return nil
+		},
+	}
+	draft := NewDraft(&mock.CLI{}, host, "develop", "", []string{"release-gr"}, nil)
+	draft.SetDryRun()
+	draft.SetPublish(nil)
+
+	if err := draft.Create(ctx, "1.1.0", "1.1.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+	calls := draft.host.(*DryRunHost).Calls()
+	if len(calls) != 2 || calls[1].Description != "publish the draft release 1.1.0" {
+		t.Errorf("unexpected recorded calls %+v", calls)
+	}
+}
+
+func TestRollbackShouldRefuseAFrozenSchedule(t *testing.T) {
+	var rolledBack int
+	host := &mock.RepositoryClient{
+		ListReleasesFn: func() ([]*ergo.Release, error) {
+			return []*ergo.Release{{TagName: "1.1.0"}, {TagName: "1.0.0"}}, nil
+		},
+		GetRefFn: func(string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha-1.1.0"}, nil
+		},
+		GetRefFromTagFn: func(tag string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha-" + tag}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			rolledBack++
+			return nil
+		},
+	}
+	deploy := &Deploy{c: &mock.CLI{}, host: host, time: mock.NewMockedTime(time.Now()), releaseBranches: []string{"release-gr"}}
+	deploy.SetFreeze(NewFreeze(&FreezeCalendar{Windows: []FreezeWindow{{Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)}}}, nil))
+
+	err := deploy.Rollback(ctx, "1ms", "1ms", "", true)
+	if err == nil || !strings.Contains(err.Error(), "--override-freeze") || rolledBack != 0 {
+		t.Errorf("expected the rollback to be refused, got %v and %d rolled back branches", err, rolledBack)
+	}
+}
@@ -0,0 +1,139 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HealthCheck describes the probe which verifies the environment of a branch after it is deployed.
+type HealthCheck struct {
+	// URL is probed with a GET request.
+	URL string
+	// ExpectedStatus is the status code of a healthy response, 200 if not set.
+	ExpectedStatus int
+	// JSONField is the dot separated path of a field of the JSON response which has to match
+	// ExpectedValue, or the tag name of the release if ExpectedValue is empty. Without it only the
+	// status is verified, and the body is never compared.
+	JSONField     string
+	ExpectedValue string
+	PollInterval  time.Duration
+	Timeout       time.Duration
+	// Rollback moves the branch back to its previous commit if the probe does not succeed.
+	Rollback bool
+}
+
+// SetHealthChecks sets the health checks of the branches, which have to succeed after a branch
+// is deployed before the next branch is deployed. The branches are matched regardless of their
+// case, since the keys of the config are lowercased.
+func (r *Deploy) SetHealthChecks(healthChecks map[string]*HealthCheck, client *http.Client) {
+	r.healthChecks = make(map[string]*HealthCheck, len(healthChecks))
+	for branch, healthCheck := range healthChecks {
+		r.healthChecks[strings.ToLower(branch)] = healthCheck
+	}
+	r.httpClient = client
+}
+
+// waitForHealthCheck probes the environment of the branch until it is healthy or the timeout
+// of the health check has passed.
+func (r *Deploy) waitForHealthCheck(ctx context.Context, branch, tagName string) error {
+	healthCheck, ok := r.healthChecks[strings.ToLower(branch)]
+	if !ok {
+		return nil
+	}
+	if r.dryRun {
+		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Would probe", healthCheck.URL, "for up to", healthCheck.Timeout.String())
+		return nil
+	}
+	r.c.PrintLine(r.time.Now().Format("15:04:05"), "Probing", healthCheck.URL)
+
+	deadline := r.time.Now().Add(healthCheck.Timeout)
+	for {
+		err := r.probe(ctx, healthCheck, tagName)
+		if err == nil {
+			r.c.PrintLine(r.time.Now().Format("15:04:05"), "Health check passed")
+			return nil
+		}
+		if !r.time.Now().Add(healthCheck.PollInterval).Before(deadline) {
+			return fmt.Errorf("health check of %s did not pass within %s: %w", branch, healthCheck.Timeout, err)
+		}
+		r.time.Sleep(healthCheck.PollInterval)
+	}
+}
+
+// probe requests the URL of the health check once and verifies the response.
+func (r *Deploy) probe(ctx context.Context, healthCheck *HealthCheck, tagName string) error {
+	client := r.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthCheck.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	expectedStatus := healthCheck.ExpectedStatus
+	if expectedStatus == 0 {
+		expectedStatus = http.StatusOK
+	}
+	if resp.StatusCode != expectedStatus {
+		return fmt.Errorf("got status %d, want %d", resp.StatusCode, expectedStatus)
+	}
+
+	if healthCheck.JSONField == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	value, err := jsonField(body, healthCheck.JSONField)
+	if err != nil {
+		return err
+	}
+	expectedValue := healthCheck.ExpectedValue
+	if expectedValue == "" {
+		expectedValue = tagName
+	}
+	if value != expectedValue {
+		return fmt.Errorf("got %s %q, want %q", healthCheck.JSONField, value, expectedValue)
+	}
+
+	return nil
+}
+
+// jsonField returns the value of the field found at the dot separated path of the JSON document.
+// Numbers are returned as they are written in the document, e.g. 1.0 or 1000000.
+func jsonField(data []byte, path string) (string, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("error parsing health check response: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return "", errors.New("field " + path + " not found")
+		}
+		if doc, ok = object[key]; !ok {
+			return "", errors.New("field " + path + " not found")
+		}
+	}
+
+	return fmt.Sprint(doc), nil
+}
@@ -0,0 +1,206 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestProbeShouldVerifyTheResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		healthCheck HealthCheck
+		wantErr     bool
+	}{
+		{name: "default status", status: http.StatusOK, healthCheck: HealthCheck{}},
+		{name: "unexpected status", status: http.StatusServiceUnavailable, healthCheck: HealthCheck{}, wantErr: true},
+		{name: "expected status", status: http.StatusNoContent, healthCheck: HealthCheck{ExpectedStatus: http.StatusNoContent}},
+		{
+			name:        "version matches the tag",
+			status:      http.StatusOK,
+			body:        `{"build": {"version": "1.1.0"}}`,
+			healthCheck: HealthCheck{JSONField: "build.version"},
+		},
+		{
+			name:        "version does not match the tag",
+			status:      http.StatusOK,
+			body:        `{"build": {"version": "1.0.0"}}`,
+			healthCheck: HealthCheck{JSONField: "build.version"},
+			wantErr:     true,
+		},
+		{
+			name:        "expected value",
+			status:      http.StatusOK,
+			body:        `{"status": "ok"}`,
+			healthCheck: HealthCheck{JSONField: "status", ExpectedValue: "ok"},
+		},
+		{
+			name:        "numeric version",
+			status:      http.StatusOK,
+			body:        `{"build": {"version": 1.0, "number": 1000000}}`,
+			healthCheck: HealthCheck{JSONField: "build.version", ExpectedValue: "1.0"},
+		},
+		{
+			name:        "large number",
+			status:      http.StatusOK,
+			body:        `{"build": {"version": 1.0, "number": 1000000}}`,
+			healthCheck: HealthCheck{JSONField: "build.number", ExpectedValue: "1000000"},
+		},
+		{
+			name:        "plain body is not compared",
+			status:      http.StatusOK,
+			body:        `1.0.0`,
+			healthCheck: HealthCheck{},
+		},
+		{
+			name:        "missing field",
+			status:      http.StatusOK,
+			body:        `{"status": "ok"}`,
+			healthCheck: HealthCheck{JSONField: "build.version"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid json",
+			status:      http.StatusOK,
+			body:        `ok`,
+			healthCheck: HealthCheck{JSONField: "status"},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			}))
+			defer server.Close()
+
+			healthCheck := test.healthCheck
+			healthCheck.URL = server.URL
+			r := &Deploy{httpClient: server.Client()}
+
+			err := r.probe(context.Background(), &healthCheck, "1.1.0")
+			if (err != nil) != test.wantErr {
+				t.Errorf("probe() returned error %v, want error: %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoShouldGateEachBranchOnItsHealthCheck(t *testing.T) {
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes++
+		if probes < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"version": "1.0.0"}`)
+	}))
+	defer server.Close()
+
+	var deployed []string
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			deployed = append(deployed, "branch")
+			return nil
+		},
+	}
+	deploy := &Deploy{
+		c:               &mock.CLI{},
+		host:            host,
+		time:            mock.NewMockedTime(time.Now()),
+		releaseBranches: []string{"branch1", "branch2"},
+		healthChecks: map[string]*HealthCheck{
+			"branch1": {URL: server.URL, JSONField: "version", PollInterval: time.Second, Timeout: time.Minute},
+		},
+		httpClient: server.Client(),
+	}
+
+	if err := deploy.Do(context.Background(), "1ms", "1ms", false, true, false); err != nil {
+		t.Fatalf("Do() should not return the error: %v", err)
+	}
+	if probes != 3 || len(deployed) != 2 {
+		t.Errorf("got %d probes and %d deployments; want 3 and 2", probes, len(deployed))
+	}
+}
+
+func TestDoShouldRollBackTheBranchWhenItsHealthCheckFails(t *testing.T) {
+	tests := []struct {
+		name         string
+		rollback     bool
+		wantRollback bool
+	}{
+		{name: "with rollback", rollback: true, wantRollback: true},
+		{name: "without rollback", rollback: false, wantRollback: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			deployments := 0
+			rolledBack := false
+			release := &ergo.Release{TagName: "1.0.0", Body: "gr ![](https://img.shields.io/badge/released-No-red.svg)"}
+			host := &mock.RepositoryClient{
+				LastReleaseFn: func() (*ergo.Release, error) {
+					return release, nil
+				},
+				GetRefFn: func(string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "previous"}, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					deployments++
+					return nil
+				},
+				UpdateBranchToSHAFn: func() error {
+					rolledBack = true
+					return nil
+				},
+			}
+			deploy := &Deploy{
+				c:                   &mock.CLI{},
+				host:                host,
+				time:                mock.NewMockedTime(time.Now()),
+				releaseBodyFind:     "-No-red.svg",
+				releaseBodyReplace:  "-green.svg",
+				releaseBranches:     []string{"Release-GR", "branch2"},
+				releaseBodyBranches: map[string]string{"Release-GR": "gr"},
+			}
+			// the keys of the config are lowercased
+			deploy.SetHealthChecks(map[string]*HealthCheck{
+				"release-gr": {URL: server.URL, PollInterval: time.Second, Timeout: 5 * time.Second, Rollback: test.rollback},
+			}, server.Client())
+
+			err := deploy.Do(context.Background(), "1ms", "1ms", false, true, false)
+			if err == nil {
+				t.Fatal("Do() should return error when the health check fails")
+			}
+			if deployments != 1 {
+				t.Errorf("expected the rollout to stop after the first branch, got %d deployments", deployments)
+			}
+			if rolledBack != test.wantRollback {
+				t.Errorf("got rollback %t; want %t", rolledBack, test.wantRollback)
+			}
+			if !strings.Contains(release.Body, "released-No-red.svg") {
+				t.Errorf("expected the badge not to be updated, got %q", release.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beatlabs/ergo"
+)
+
+// DeployPlan is the persisted state of a deployment, used to resume an interrupted deployment.
+type DeployPlan struct {
+	Repo           string        `json:"repo"`
+	ReleaseID      int64         `json:"release_id"`
+	TagName        string        `json:"tag_name"`
+	ReleaseURL     string        `json:"release_url"`
+	AllowForcePush bool          `json:"allow_force_push"`
+	Branches       []*BranchPlan `json:"branches"`
+}
+
+// BranchPlan is the planned start time and progress of the deployment of a branch.
+type BranchPlan struct {
+	Branch      string     `json:"branch"`
+	StartTime   time.Time  `json:"start_time"`
+	TriggeredAt *time.Time `json:"triggered_at,omitempty"`
+}
+
+// newDeployPlan creates the plan of deploying the release to the branches starting at the release time.
+func newDeployPlan(
+	repo string,
+	release *ergo.Release,
+	allowForcePush bool,
+	releaseTime time.Time,
+	branches []string,
+	intervalDurations []time.Duration,
+) *DeployPlan {
+	plan := &DeployPlan{
+		Repo:           repo,
+		ReleaseID:      release.ID,
+		TagName:        release.TagName,
+		ReleaseURL:     release.ReleaseURL,
+		AllowForcePush: allowForcePush,
+	}
+	for i, branch := range branches {
+		plan.Branches = append(plan.Branches, &BranchPlan{Branch: branch, StartTime: releaseTime})
+		releaseTime = releaseTime.Add(intervalDurations[i%len(intervalDurations)])
+	}
+	return plan
+}
+
+// loadDeployPlan reads the plan from the state file. It returns nil if the file does not exist.
+func loadDeployPlan(path string) (*DeployPlan, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading deploy state file: %w", err)
+	}
+
+	plan := &DeployPlan{}
+	if err = json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("error parsing deploy state file %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// save writes the plan to the state file.
+func (p *DeployPlan) save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing deploy state file: %w", err)
+	}
+	return nil
+}
+
+// pending returns the branches which have not been triggered yet.
+func (p *DeployPlan) pending() []*BranchPlan {
+	var pending []*BranchPlan
+	for _, b := range p.Branches {
+		if b.TriggeredAt == nil {
+			pending = append(pending, b)
+		}
+	}
+	return pending
+}
+
+// markTriggered records that the branch has been triggered.
+func (p *DeployPlan) markTriggered(branch string, t time.Time) {
+	for _, b := range p.Branches {
+		if b.Branch == branch && b.TriggeredAt == nil {
+			b.TriggeredAt = &t
+			return
+		}
+	}
+}
+
+// replan shifts the start times of the pending branches so that the first one starts at the
+// given time, keeping the intervals between them.
+func (p *DeployPlan) replan(start time.Time) {
+	pending := p.pending()
+	if len(pending) == 0 {
+		return
+	}
+	shift := start.Sub(pending[0].StartTime)
+	for _, b := range pending {
+		b.StartTime = b.StartTime.Add(shift)
+	}
+}
@@ -0,0 +1,62 @@
+package release
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+)
+
+func TestDeployPlanShouldBeSavedAndLoaded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	start := time.Date(2022, 8, 4, 13, 37, 0, 0, time.UTC)
+
+	plan := newDeployPlan("o/r", &ergo.Release{ID: 1, TagName: "1.0.0"}, true, start,
+		[]string{"b1", "b2", "b3"}, []time.Duration{10 * time.Minute, 5 * time.Minute})
+	plan.markTriggered("b1", start)
+	if err := plan.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadDeployPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TagName != "1.0.0" || !got.AllowForcePush || len(got.Branches) != 3 {
+		t.Fatalf("unexpected plan %+v", got)
+	}
+	if want := start.Add(15 * time.Minute); !got.Branches[2].StartTime.Equal(want) {
+		t.Errorf("expected start time %v, got %v", want, got.Branches[2].StartTime)
+	}
+	if pending := got.pending(); len(pending) != 2 || pending[0].Branch != "b2" {
+		t.Errorf("expected b2 and b3 to be pending, got %v", pending)
+	}
+}
+
+func TestLoadDeployPlanShouldReturnNilForMissingFile(t *testing.T) {
+	plan, err := loadDeployPlan(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan != nil {
+		t.Error("expected no plan for a missing state file")
+	}
+}
+
+func TestReplanShouldKeepTheIntervalsOfThePendingBranches(t *testing.T) {
+	start := time.Date(2022, 8, 4, 13, 37, 0, 0, time.UTC)
+	plan := newDeployPlan("o/r", &ergo.Release{}, false, start,
+		[]string{"b1", "b2", "b3"}, []time.Duration{10 * time.Minute, 5 * time.Minute})
+	plan.markTriggered("b1", start)
+
+	now := start.Add(time.Hour)
+	plan.replan(now)
+
+	if !plan.Branches[0].StartTime.Equal(start) {
+		t.Errorf("expected triggered branch to keep its start time")
+	}
+	if !plan.Branches[1].StartTime.Equal(now) || !plan.Branches[2].StartTime.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("unexpected start times %v, %v", plan.Branches[1].StartTime, plan.Branches[2].StartTime)
+	}
+}
@@ -0,0 +1,106 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beatlabs/ergo"
+)
+
+// SetPullRequestNotes makes the release body list the merged pull requests of the commits instead
+// of the commits, grouped in sections by label. The label sections map labels to section titles.
+func (d *Draft) SetPullRequestNotes(labelSections map[string]string) {
+	d.pullRequestNotes = true
+	d.labelSections = labelSections
+}
+
+// mergedPullRequests resolves the commits to their merged pull requests, in the order of the
+// commits, and returns the commits without a merged pull request too.
+func (d *Draft) mergedPullRequests(ctx context.Context, commits []*ergo.Commit) ([]*ergo.PullRequest, []*ergo.Commit, error) {
+	var pulls []*ergo.PullRequest
+	var unmerged []*ergo.Commit
+	seen := make(map[int]bool)
+	for _, commit := range commits {
+		commitPulls, err := d.host.ListPullRequestsWithCommit(ctx, commit.SHA)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(commitPulls) == 0 {
+			unmerged = append(unmerged, commit)
+		}
+		for _, pull := range commitPulls {
+			if seen[pull.Number] {
+				continue
+			}
+			seen[pull.Number] = true
+			pulls = append(pulls, pull)
+		}
+	}
+	return pulls, unmerged, nil
+}
+
+// pullRequestSections groups the pull requests by the section of their first mapped label. The
+// sections are sorted by title, followed by the pull requests without a mapped label and the
+// commits without a pull request.
+func pullRequestSections(pulls []*ergo.PullRequest, unmerged []*ergo.Commit, labelSections map[string]string) []*BodySection {
+	sections := make(map[string]*BodySection)
+	for _, pull := range pulls {
+		title := otherSection
+		for _, label := range pull.Labels {
+			if section, ok := labelSections[strings.ToLower(label)]; ok {
+				title = section
+				break
+			}
+		}
+		if _, ok := sections[title]; !ok {
+			sections[title] = &BodySection{Title: title}
+		}
+		sections[title].PullRequests = append(sections[title].PullRequests, pull)
+	}
+	if len(unmerged) > 0 {
+		if _, ok := sections[otherSection]; !ok {
+			sections[otherSection] = &BodySection{Title: otherSection}
+		}
+		sections[otherSection].Commits = unmerged
+	}
+
+	sorted := make([]*BodySection, 0, len(sections))
+	for title, section := range sections {
+		if title != otherSection {
+			sorted = append(sorted, section)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Title < sorted[j].Title })
+	if other, ok := sections[otherSection]; ok {
+		sorted = append(sorted, other)
+	}
+	return sorted
+}
+
+// pullRequestsBody renders the default release body of the pull requests.
+func (d *Draft) pullRequestsBody(model *BodyModel) string {
+	lineSeparator := "\r\n"
+
+	parts := []string{model.Markers()}
+	if model.Prefix != "" {
+		parts = append(parts, model.Prefix)
+	}
+	for _, section := range model.Sections {
+		lines := []string{section.Title}
+		for _, pull := range section.PullRequests {
+			line := fmt.Sprintf("- %s (#%d) @%s", pull.Title, pull.Number, pull.Author)
+			for _, label := range pull.Labels {
+				line += fmt.Sprintf(" `%s`", label)
+			}
+			lines = append(lines, line)
+		}
+		for _, commit := range section.Commits {
+			lines = append(lines, d.formatMessage(commit, "- ", "  ", lineSeparator))
+		}
+		parts = append(parts, strings.Join(lines, lineSeparator))
+	}
+
+	return strings.Join(parts, strings.Repeat(lineSeparator, 2))
+}
@@ -0,0 +1,75 @@
+package release
+
+import (
+	"context"
+	"testing"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestCreateShouldListTheMergedPullRequestsByLabel(t *testing.T) {
+	pulls := map[string][]*ergo.PullRequest{
+		"a": {{Number: 1, Title: "Add login", Author: "alice", Labels: []string{"Feature"}}},
+		"b": {{Number: 1, Title: "Add login", Author: "alice", Labels: []string{"Feature"}}},
+		"c": {{Number: 2, Title: "Fix crash", Author: "bob", Labels: []string{"ui", "bug"}}},
+		"d": {{Number: 3, Title: "Bump deps", Author: "carol"}},
+		"e": nil,
+	}
+	commits := []*ergo.Commit{{SHA: "a"}, {SHA: "b"}, {SHA: "c"}, {SHA: "d"}, {SHA: "e", Message: "Hotfix config\n\nwithout a pull request"}}
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{
+				Branch: "release-gr",
+				Behind: commits,
+			}}, nil
+		},
+		ListPullRequestsWithCommitFn: func(sha string) ([]*ergo.PullRequest, error) {
+			return pulls[sha], nil
+		},
+	}
+
+	c := &mock.CLI{}
+	draft := NewDraft(c, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetPullRequestNotes(map[string]string{"feature": "### Added", "bug": "### Fixed"})
+
+	if err := draft.Create(context.Background(), "", "1.2.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+
+	want := "release-gr ![](https://img.shields.io/badge/released-No-red.svg)\r\n\r\n" +
+		"### Added\r\n- Add login (#1) @alice `Feature`\r\n\r\n" +
+		"### Fixed\r\n- Fix crash (#2) @bob `ui` `bug`\r\n\r\n" +
+		"### Other\r\n- Bump deps (#3) @carol\r\n- Hotfix config\r\n  without a pull request"
+	if len(c.PrintLines) != 1 || c.PrintLines[0] != want {
+		t.Errorf("got body %q; want %q", c.PrintLines, want)
+	}
+}
+
+func TestCreateShouldRenderThePullRequestSectionsWithTheTemplate(t *testing.T) {
+	host := &mock.RepositoryClient{
+		DiffCommitsFn: func() ([]*ergo.StatusReport, error) {
+			return []*ergo.StatusReport{{Branch: "release-gr", Behind: []*ergo.Commit{{SHA: "a"}}}}, nil
+		},
+		ListPullRequestsWithCommitFn: func(sha string) ([]*ergo.PullRequest, error) {
+			return []*ergo.PullRequest{{Number: 1, Title: "Add login", Labels: []string{"feature"}}}, nil
+		},
+	}
+
+	bodyTemplate, err := ParseBodyTemplate("{{ range .Sections }}{{ .Title }}:{{ range .PullRequests }} #{{ .Number }}{{ end }}{{ end }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &mock.CLI{}
+	draft := NewDraft(c, host, "master", "", []string{"release-gr"}, nil)
+	draft.SetPullRequestNotes(map[string]string{"feature": "Added"})
+	draft.SetBodyTemplate(bodyTemplate)
+
+	if err = draft.Create(context.Background(), "", "1.2.0", true); err != nil {
+		t.Fatalf("Create should not return the error: %v", err)
+	}
+	if len(c.PrintLines) != 1 || c.PrintLines[0] != "Added: #1" {
+		t.Errorf("unexpected body %q", c.PrintLines)
+	}
+}
@@ -0,0 +1,237 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/cli"
+)
+
+// Rollback restores every release branch to the release published before the one it is at, or to
+// the given tag, following the same staged schedule as a deployment.
+func (r *Deploy) Rollback(
+	ctx context.Context,
+	releaseIntervalInput string,
+	releaseOffsetInput string,
+	tagName string,
+	skipConfirm bool,
+) error {
+	targets, err := r.rollbackTargets(ctx, tagName)
+	if err != nil {
+		return err
+	}
+
+	r.c.PrintColorizedLine("REPO: ", r.host.GetRepoName(), cli.WarningType)
+	for i, branch := range r.releaseBranches {
+		from := "its head"
+		if targets[i].current != nil {
+			from = targets[i].current.TagName
+		}
+		r.c.PrintLine("Rolling back", branch, "from", from, "to", targets[i].target.TagName)
+	}
+	r.c.PrintLine("Rollback start times are estimates.")
+
+	intervalDurations, releaseTimer, err := r.calculateReleaseTime(releaseIntervalInput, releaseOffsetInput)
+	if err != nil {
+		return err
+	}
+
+	// without confirmation the rollback starts right away
+	releaseTime := *releaseTimer
+	if skipConfirm {
+		releaseTime = r.time.Now()
+	}
+
+	r.printReleaseTimeBoard(releaseTime, r.releaseBranches, intervalDurations)
+
+	if err = r.checkFreeze(releaseTime, r.releaseBranches, intervalDurations); err != nil {
+		return err
+	}
+
+	if skipConfirm {
+		if err = r.recordRollbackFreezeOverride(ctx, targets); err != nil {
+			return err
+		}
+		return r.rollbackAllReleaseBranches(ctx, intervalDurations, targets)
+	}
+
+	confirm, err := r.c.Confirmation("Rollback", "No rollback", "")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return nil
+	}
+
+	if releaseTime.Before(r.time.Now()) {
+		return errors.New("rollback stopped since first released time has passed. Please run again")
+	}
+
+	if err = r.recordRollbackFreezeOverride(ctx, targets); err != nil {
+		return err
+	}
+
+	untilReleaseTime := releaseTime.Sub(r.time.Now())
+	r.c.PrintLine("Rollback will start in", untilReleaseTime.String())
+	r.time.Sleep(untilReleaseTime)
+
+	return r.rollbackAllReleaseBranches(ctx, intervalDurations, targets)
+}
+
+// rollbackTarget is the published release a release branch is at, if any, and the release it is
+// rolled back to.
+type rollbackTarget struct {
+	current *ergo.Release
+	target  *ergo.Release
+}
+
+// rollbackTargets returns the rollback target of every release branch. The release a branch is at
+// is the published release whose tag points to the head of the branch, and it is rolled back to the
+// given tag or to the published release before that one.
+func (r *Deploy) rollbackTargets(ctx context.Context, tagName string) ([]rollbackTarget, error) {
+	releases, err := r.host.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var published []*ergo.Release
+	var given *ergo.Release
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		published = append(published, release)
+		if release.TagName == tagName {
+			given = release
+		}
+	}
+	if len(published) == 0 {
+		return nil, errors.New("no published release found to roll back")
+	}
+	if tagName != "" && given == nil {
+		return nil, fmt.Errorf("release %s not found", tagName)
+	}
+
+	tagSHAs := make(map[string]string)
+	targets := make([]rollbackTarget, 0, len(r.releaseBranches))
+	for _, branch := range r.releaseBranches {
+		ref, err := r.host.GetRef(ctx, branch)
+		if err != nil {
+			return nil, err
+		}
+		if ref == nil {
+			return nil, fmt.Errorf("release branch %s not found", branch)
+		}
+
+		current := -1
+		for i, release := range published {
+			sha, ok := tagSHAs[release.TagName]
+			if !ok {
+				tagRef, err := r.host.GetRefFromTag(ctx, release.TagName)
+				if err != nil {
+					return nil, err
+				}
+				if tagRef != nil {
+					sha = tagRef.SHA
+				}
+				tagSHAs[release.TagName] = sha
+			}
+			if sha != "" && sha == ref.SHA {
+				current = i
+				break
+			}
+		}
+
+		switch {
+		case given != nil && current < 0:
+			targets = append(targets, rollbackTarget{target: given})
+		case given != nil:
+			targets = append(targets, rollbackTarget{current: published[current], target: given})
+		case current < 0:
+			return nil, fmt.Errorf("release branch %s is not at a published release, roll it back with --tag", branch)
+		case current == len(published)-1:
+			return nil, fmt.Errorf("no release found before %s to roll back %s to", published[current].TagName, branch)
+		default:
+			targets = append(targets, rollbackTarget{current: published[current], target: published[current+1]})
+		}
+	}
+	return targets, nil
+}
+
+// recordRollbackFreezeOverride records the freeze override in the body of every release the
+// release branches are rolled back from.
+func (r *Deploy) recordRollbackFreezeOverride(ctx context.Context, targets []rollbackTarget) error {
+	recorded := make(map[*ergo.Release]bool)
+	for _, target := range targets {
+		if target.current == nil || recorded[target.current] {
+			continue
+		}
+		recorded[target.current] = true
+		if err := r.recordFreezeOverride(ctx, target.current, "rollback"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Deploy) rollbackAllReleaseBranches(
+	ctx context.Context,
+	intervalDurations []time.Duration,
+	targets []rollbackTarget,
+) error {
+	for i, branch := range r.releaseBranches {
+		r.c.PrintLine("Rolling back", r.time.Now().Format("15:04:05"), branch)
+
+		if err := r.host.UpdateBranchFromTag(ctx, targets[i].target.TagName, branch, true); err != nil {
+			return err
+		}
+		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Triggered Successfully")
+
+		if targets[i].current != nil {
+			if err := r.markRolledBack(ctx, targets[i].current, branch, targets[i].target.TagName); err != nil {
+				return err
+			}
+		}
+
+		// Don't sleep after the last rollback
+		if i < (len(r.releaseBranches) - 1) {
+			intervalDuration := intervalDurations[i%len(intervalDurations)]
+			r.time.Sleep(intervalDuration)
+		}
+	}
+	return nil
+}
+
+// markRolledBack replaces the badge of the branch in the body of the rolled back release.
+func (r *Deploy) markRolledBack(ctx context.Context, release *ergo.Release, branch, tagName string) error {
+	if r.releaseBodyFind == "" {
+		return nil
+	}
+	branchText, ok := r.releaseBodyBranches[branch]
+	if !ok {
+		branchText = branch
+	}
+
+	badge := regexp.MustCompile(regexp.QuoteMeta(branchText+" ![](https://img.shields.io/badge/released") + `[^)]*\)`)
+	t := r.time.Now()
+	replaceText := fmt.Sprintf("%s ![](https://img.shields.io/badge/rolled_back_to_%s-%d_%s_%d_%02d:%02d-orange.svg)",
+		branchText, badgeText(tagName), t.Day(), t.Month(), t.Year(), t.Hour(), t.Minute())
+
+	newBody := badge.ReplaceAllLiteralString(release.Body, replaceText)
+	if newBody == release.Body {
+		return nil
+	}
+	release.Body = newBody
+	_, err := r.host.EditRelease(ctx, release)
+	return err
+}
+
+// badgeText escapes the dashes and underscores of a shields.io badge text.
+func badgeText(text string) string {
+	return strings.NewReplacer("-", "--", "_", "__").Replace(text)
+}
@@ -0,0 +1,160 @@
+package release
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestRollbackShouldRestoreTheBranchesToThePreviousRelease(t *testing.T) {
+	tests := []struct {
+		name      string
+		tagName   string
+		wantLines []string
+		wantGr    string
+		wantPe    string
+	}{
+		{
+			name:      "previous release of each branch",
+			wantLines: []string{"Rolling back branch1 from 1.1.0 to 1.0.0", "Rolling back branch2 from 1.0.0 to 0.9.0"},
+			wantGr:    "gr ![](https://img.shields.io/badge/rolled_back_to_1.0.0-17_October_2026_12:30-orange.svg)",
+			wantPe:    "branch2 ![](https://img.shields.io/badge/rolled_back_to_0.9.0-17_October_2026_12:30-orange.svg)",
+		},
+		{
+			name:      "given release",
+			tagName:   "0.9.0",
+			wantLines: []string{"Rolling back branch1 from 1.1.0 to 0.9.0", "Rolling back branch2 from 1.0.0 to 0.9.0"},
+			wantGr:    "gr ![](https://img.shields.io/badge/rolled_back_to_0.9.0-17_October_2026_12:30-orange.svg)",
+			wantPe:    "branch2 ![](https://img.shields.io/badge/rolled_back_to_0.9.0-17_October_2026_12:30-orange.svg)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			latest := &ergo.Release{TagName: "1.1.0", Body: "gr ![](https://img.shields.io/badge/released-1_January_2026_10:00-green.svg) branch2 ![](https://img.shields.io/badge/released-No-red.svg)"}
+			previous := &ergo.Release{TagName: "1.0.0", Body: "gr ![](https://img.shields.io/badge/released-1_December_2025_10:00-green.svg) branch2 ![](https://img.shields.io/badge/released-2_December_2025_10:00-green.svg)"}
+			releases := []*ergo.Release{{TagName: "1.2.0", Draft: true}, latest, previous, {TagName: "0.9.0"}}
+			heads := map[string]string{"branch1": "sha-1.1.0", "branch2": "sha-1.0.0"}
+			rolledBack := 0
+			host := &mock.RepositoryClient{
+				ListReleasesFn: func() ([]*ergo.Release, error) {
+					return releases, nil
+				},
+				GetRefFn: func(branch string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: heads[branch]}, nil
+				},
+				GetRefFromTagFn: func(tag string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "sha-" + tag}, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					rolledBack++
+					return nil
+				},
+			}
+			c := &mock.CLI{}
+			deploy := &Deploy{
+				c:                   c,
+				host:                host,
+				time:                mock.NewMockedTime(time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)),
+				releaseBodyFind:     "-No-red.svg",
+				releaseBranches:     []string{"branch1", "branch2"},
+				releaseBodyBranches: map[string]string{"branch1": "gr"},
+			}
+
+			if err := deploy.Rollback(context.Background(), "1ms", "1ms", test.tagName, true); err != nil {
+				t.Fatalf("Rollback() should not return the error: %v", err)
+			}
+			if rolledBack != 2 {
+				t.Errorf("expected 2 rolled back branches, got %d", rolledBack)
+			}
+			if !reflect.DeepEqual(c.PrintLines[:2], test.wantLines) {
+				t.Errorf("expected the output %q, got %q", test.wantLines, c.PrintLines[:2])
+			}
+			if !strings.Contains(latest.Body, test.wantGr) || !strings.Contains(latest.Body, "branch2 ![](https://img.shields.io/badge/released-No-red.svg)") {
+				t.Errorf("unexpected latest release body %q", latest.Body)
+			}
+			if !strings.Contains(previous.Body, test.wantPe) || !strings.Contains(previous.Body, "gr ![](https://img.shields.io/badge/released-1_December") {
+				t.Errorf("unexpected previous release body %q", previous.Body)
+			}
+		})
+	}
+}
+
+func TestRollbackShouldWaitForTheOffsetOnTheClock(t *testing.T) {
+	now := time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC)
+	edits := 0
+	host := &mock.RepositoryClient{
+		ListReleasesFn: func() ([]*ergo.Release, error) {
+			return []*ergo.Release{{TagName: "1.1.0", Body: "no badges"}, {TagName: "1.0.0"}}, nil
+		},
+		GetRefFn: func(string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha-1.1.0"}, nil
+		},
+		GetRefFromTagFn: func(tag string) (*ergo.Reference, error) {
+			return &ergo.Reference{SHA: "sha-" + tag}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			return nil
+		},
+		EditReleaseFn: func() (*ergo.Release, error) {
+			edits++
+			return nil, nil
+		},
+	}
+	clock := mock.NewMockedTime(now)
+	deploy := &Deploy{c: &mock.CLI{}, host: host, time: clock, releaseBodyFind: "-No-red.svg", releaseBranches: []string{"branch1"}}
+
+	if err := deploy.Rollback(context.Background(), "1ms", "1h", "", false); err != nil {
+		t.Fatalf("Rollback() should not return the error: %v", err)
+	}
+	if want := now.Add(time.Hour); clock.Now().Before(want) {
+		t.Errorf("expected the rollback to start at %v, got %v", want, clock.Now())
+	}
+	if edits != 0 {
+		t.Errorf("expected the release without badges not to be edited, got %d edits", edits)
+	}
+}
+
+func TestRollbackShouldReturnErrorWithoutAPreviousRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		releases []*ergo.Release
+		tagName  string
+	}{
+		{name: "no releases"},
+		{name: "single release", releases: []*ergo.Release{{TagName: "1.0.0"}}},
+		{name: "draft release", releases: []*ergo.Release{{TagName: "1.1.0", Draft: true}, {TagName: "1.0.0"}}},
+		{name: "unknown tag", releases: []*ergo.Release{{TagName: "1.1.0"}, {TagName: "1.0.0"}}, tagName: "0.1.0"},
+		{name: "branch not at a release", releases: []*ergo.Release{{TagName: "1.1.0"}, {TagName: "0.9.0"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			host := &mock.RepositoryClient{
+				ListReleasesFn: func() ([]*ergo.Release, error) {
+					return test.releases, nil
+				},
+				GetRefFn: func(string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "sha-1.0.0"}, nil
+				},
+				GetRefFromTagFn: func(tag string) (*ergo.Reference, error) {
+					return &ergo.Reference{SHA: "sha-" + tag}, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					t.Error("no branch should be rolled back")
+					return nil
+				},
+			}
+			deploy := &Deploy{c: &mock.CLI{}, host: host, time: &mock.Time{}, releaseBranches: []string{"branch1"}}
+
+			if err := deploy.Rollback(context.Background(), "1ms", "1ms", test.tagName, true); err == nil {
+				t.Error("Rollback() should return error")
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Wave is a group of release branches deployed together. The next wave starts after the delay
+// and, if it requires confirmation, once the deployment is confirmed.
+type Wave struct {
+	Name     string
+	Branches []string
+	Delay    time.Duration
+	Confirm  bool
+}
+
+// SetWaves replaces the release branches and the intervals between them with the waves of a
+// rollout plan.
+func (r *Deploy) SetWaves(waves []Wave) {
+	r.releaseBranches = nil
+	r.waveIntervals = nil
+	r.waveConfirmations = make(map[string]string)
+	for _, wave := range waves {
+		for i, branch := range wave.Branches {
+			r.releaseBranches = append(r.releaseBranches, branch)
+			if i < len(wave.Branches)-1 {
+				r.waveIntervals = append(r.waveIntervals, 0)
+			} else {
+				r.waveIntervals = append(r.waveIntervals, wave.Delay)
+			}
+		}
+		if wave.Confirm && len(wave.Branches) > 0 {
+			r.waveConfirmations[wave.Branches[0]] = wave.Name
+		}
+	}
+}
+
+// confirmWave asks for the confirmation of the wave starting with the branch, if it requires one.
+// The deployment stops if it is not confirmed.
+func (r *Deploy) confirmWave(branch string) error {
+	name, ok := r.waveConfirmations[branch]
+	if !ok {
+		return nil
+	}
+	if r.dryRun {
+		r.c.PrintLine(r.time.Now().Format("15:04:05"), "Would ask for the confirmation of wave", name)
+		return nil
+	}
+
+	confirm, err := r.c.Confirmation("Deploy wave "+name, "", "")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return fmt.Errorf("deployment stopped before wave %s, run deploy with --resume to continue", name)
+	}
+	return nil
+}
+
+// SelectWaves returns the waves, or the branches of the waves, named in only, all of them if only
+// is empty, without the waves or branches named in skip. Waves left without branches are
+// dropped. A name matching no wave or branch is an error.
+func SelectWaves(waves []Wave, only, skip []string) ([]Wave, error) {
+	known := make(map[string]bool)
+	for _, wave := range waves {
+		known[wave.Name] = true
+		for _, branch := range wave.Branches {
+			known[branch] = true
+		}
+	}
+	for _, name := range append(append([]string(nil), only...), skip...) {
+		if !known[name] {
+			return nil, fmt.Errorf("no wave or branch named %s", name)
+		}
+	}
+
+	selected := make([]Wave, 0, len(waves))
+	for _, wave := range waves {
+		var branches []string
+		for _, branch := range wave.Branches {
+			included := len(only) == 0 || inNames(only, wave.Name) || inNames(only, branch)
+			if included && !inNames(skip, wave.Name) && !inNames(skip, branch) {
+				branches = append(branches, branch)
+			}
+		}
+		if len(branches) > 0 {
+			wave.Branches = branches
+			selected = append(selected, wave)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no branches left to deploy")
+	}
+	return selected, nil
+}
+
+// inNames reports whether the name is one of the names.
+func inNames(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package release
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func rolloutPlan() []Wave {
+	return []Wave{
+		{Name: "canary", Branches: []string{"release-gr"}, Delay: 30 * time.Minute},
+		{Name: "latam", Branches: []string{"release-mx", "release-pe"}, Delay: time.Hour, Confirm: true},
+		{Name: "rest", Branches: []string{"release-co"}},
+	}
+}
+
+func TestSelectWavesShouldSelectWavesAndBranches(t *testing.T) {
+	tests := map[string]struct {
+		only, skip []string
+		want       map[string][]string
+		wantErr    bool
+	}{
+		"all":            {want: map[string][]string{"canary": {"release-gr"}, "latam": {"release-mx", "release-pe"}, "rest": {"release-co"}}},
+		"only a wave":    {only: []string{"latam"}, want: map[string][]string{"latam": {"release-mx", "release-pe"}}},
+		"only a branch":  {only: []string{"release-gr", "release-pe"}, want: map[string][]string{"canary": {"release-gr"}, "latam": {"release-pe"}}},
+		"skip a wave":    {skip: []string{"canary"}, want: map[string][]string{"latam": {"release-mx", "release-pe"}, "rest": {"release-co"}}},
+		"skip a branch":  {only: []string{"latam"}, skip: []string{"release-mx"}, want: map[string][]string{"latam": {"release-pe"}}},
+		"unknown name":   {only: []string{"release-xx"}, wantErr: true},
+		"nothing to run": {skip: []string{"canary", "latam", "rest"}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			waves, err := SelectWaves(rolloutPlan(), tt.only, tt.skip)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", waves)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectWaves should not return the error: %v", err)
+			}
+			got := make(map[string][]string)
+			for _, wave := range waves {
+				got[wave.Name] = wave.Branches
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDoShouldDeployTheWavesWithTheirDelays(t *testing.T) {
+	now := time.Date(2026, 10, 20, 14, 0, 0, 0, time.UTC)
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+	}
+	c := &mock.CLI{}
+
+	deploy := &Deploy{c: c, host: host, time: mock.NewMockedTime(now)}
+	deploy.SetWaves(rolloutPlan())
+	if err := deploy.Do(ctx, "25m", "0s", false, true, false); err != nil {
+		t.Fatalf("Do should not return the error: %v", err)
+	}
+
+	var deploying []string
+	for _, line := range c.PrintLines {
+		if strings.HasPrefix(line, "Deploying ") && strings.Contains(line, "release-") {
+			deploying = append(deploying, line)
+		}
+	}
+	want := []string{
+		"Deploying 14:00:00 release-gr",
+		"Deploying 14:30:00 release-mx",
+		"Deploying 14:30:00 release-pe",
+		"Deploying 15:30:00 release-co",
+	}
+	if !reflect.DeepEqual(deploying, want) {
+		t.Errorf("expected %v, got %v", want, deploying)
+	}
+	if c.ConfirmationCalls != 1 {
+		t.Errorf("expected the confirmation of the latam wave, got %d confirmations", c.ConfirmationCalls)
+	}
+}
+
+func TestDoShouldStopBeforeAWaveWhichIsNotConfirmed(t *testing.T) {
+	var updated []string
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+	}
+	host.UpdateBranchFromTagFn = func() error {
+		updated = append(updated, "")
+		return nil
+	}
+	c := &mock.CLI{ConfirmationFn: func() (bool, error) {
+		return false, nil
+	}}
+
+	deploy := &Deploy{c: c, host: host, time: mock.NewMockedTime(time.Now())}
+	deploy.SetWaves(rolloutPlan())
+	err := deploy.Do(ctx, "25m", "0s", false, true, false)
+	if err == nil || !strings.Contains(err.Error(), "before wave latam") {
+		t.Errorf("expected the deployment to stop before the latam wave, got %v", err)
+	}
+	if len(updated) != 1 {
+		t.Errorf("expected only the canary wave to be deployed, got %d branches", len(updated))
+	}
+}
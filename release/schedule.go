@@ -0,0 +1,68 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/beatlabs/ergo"
+)
+
+// SetStartTime schedules the first release branch at the time, instead of after the release
+// offset.
+func (r *Deploy) SetStartTime(startTime time.Time) {
+	r.startTime = startTime
+}
+
+// SetScheduleLocation prints the schedule board in the time zone of the schedule when no board
+// time zones are set.
+func (r *Deploy) SetScheduleLocation(location *time.Location) {
+	r.location = location
+}
+
+// SetBoardLocations prints the schedule board in the time zones, instead of the time zone of the
+// schedule.
+func (r *Deploy) SetBoardLocations(locations []*time.Location) {
+	r.boardLocations = locations
+}
+
+// SetFreeze makes the deployment check the start time of every release branch against the
+// freeze calendar before it starts.
+func (r *Deploy) SetFreeze(freeze *Freeze) {
+	r.freeze = freeze
+}
+
+// checkFreeze checks the schedule of the release branches against the freeze calendar, if any,
+// and remembers whether the freeze is overridden, so that the override is recorded in the body
+// of the release.
+func (r *Deploy) checkFreeze(releaseTime time.Time, releaseBranches []string, intervalDurations []time.Duration) error {
+	if r.freeze == nil || len(releaseBranches) == 0 {
+		return nil
+	}
+	overridden, err := r.freeze.Check(r.c, releaseBranches, releaseTimes(releaseTime, releaseBranches, intervalDurations))
+	r.freezeOverridden = overridden
+	return err
+}
+
+// recordFreezeOverride records the override of the freeze by the command in the body of the
+// release, if the freeze was overridden.
+func (r *Deploy) recordFreezeOverride(ctx context.Context, release *ergo.Release, command string) error {
+	if !r.freezeOverridden {
+		return nil
+	}
+	release.Body = r.freeze.RecordOverride(release.Body, command, r.time.Now())
+	if _, err := r.host.EditRelease(ctx, release); err != nil {
+		return fmt.Errorf("error recording the freeze override: %w", err)
+	}
+	return nil
+}
+
+// releaseTimes returns the start time of every release branch.
+func releaseTimes(releaseTime time.Time, releaseBranches []string, intervalDurations []time.Duration) []time.Time {
+	times := make([]time.Time, 0, len(releaseBranches))
+	for i := range releaseBranches {
+		times = append(times, releaseTime)
+		releaseTime = releaseTime.Add(intervalDurations[i%len(intervalDurations)])
+	}
+	return times
+}
@@ -0,0 +1,174 @@
+package release
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beatlabs/ergo"
+	"github.com/beatlabs/ergo/mock"
+)
+
+func TestDoShouldStartAtTheStartTime(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, athens)
+	startTime := time.Date(2026, 10, 20, 14, 0, 0, 0, athens)
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+	}
+	clock := mock.NewMockedTime(now)
+	deploy := &Deploy{c: &mock.CLI{}, host: host, time: clock, releaseBranches: []string{"release-gr", "release-mx"}}
+	deploy.SetStartTime(startTime)
+
+	if err = deploy.Do(ctx, "10m", "1m", false, true, false); err != nil {
+		t.Fatalf("Do should not return the error: %v", err)
+	}
+	if want := startTime.Add(10 * time.Minute); !clock.Now().Equal(want) {
+		t.Errorf("expected the last branch to be deployed at %v, got %v", want, clock.Now())
+	}
+}
+
+func TestDoShouldCheckTheFreezeAtTheTimeADeploymentWithoutConfirmationStarts(t *testing.T) {
+	now := time.Date(2026, 11, 27, 11, 0, 0, 0, time.UTC)
+	updated := 0
+	host := &mock.RepositoryClient{
+		LastReleaseFn: func() (*ergo.Release, error) {
+			return &ergo.Release{TagName: "1.0.0"}, nil
+		},
+		UpdateBranchFromTagFn: func() error {
+			updated++
+			return nil
+		},
+	}
+	cliMock := &mock.CLI{}
+	deploy := &Deploy{c: cliMock, host: host, time: mock.NewMockedTime(now), releaseBranches: []string{"release-gr"}}
+	// the deployment starts now, in the window, and not after the offset, after the window
+	deploy.SetFreeze(NewFreeze(&FreezeCalendar{Windows: []FreezeWindow{{Start: now.Add(-time.Hour), End: now.Add(10 * time.Minute)}}}, nil))
+
+	err := deploy.Do(ctx, "1m", "30m", false, true, false)
+	if err == nil || !strings.Contains(err.Error(), "--override-freeze") {
+		t.Errorf("expected the freeze error, got %v", err)
+	}
+	if updated != 0 {
+		t.Errorf("expected no branch to be deployed, got %d", updated)
+	}
+	if rows := cliMock.PrintTableCalls[0].Values; rows[0][1] != "11:00 UTC" {
+		t.Errorf("expected the board to show the start time of the deployment, got %v", rows)
+	}
+}
+
+func TestDoShouldRefuseABranchPlannedInAFreezeWindow(t *testing.T) {
+	now := time.Date(2026, 11, 27, 11, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		window   FreezeWindow
+		override string
+		wantErr  bool
+	}{
+		"before":          {window: FreezeWindow{Start: now.Add(-2 * time.Hour), End: now}},
+		"after":           {window: FreezeWindow{Start: now.Add(90 * time.Minute), End: now.Add(2 * time.Hour)}},
+		"between":         {window: FreezeWindow{Start: now.Add(15 * time.Minute), End: now.Add(20 * time.Minute)}, wantErr: true},
+		"on a branch":     {window: FreezeWindow{Start: now.Add(25 * time.Minute), End: now.Add(35 * time.Minute), Reason: "Black Friday"}, wantErr: true},
+		"around":          {window: FreezeWindow{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}, wantErr: true},
+		"overridden":      {window: FreezeWindow{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}, override: "hotfix for the outage"},
+		"not overridable": {window: FreezeWindow{Start: now.Add(-time.Hour), End: now}, override: "not needed"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var updated int
+			release := &ergo.Release{TagName: "1.0.0", Body: "release body"}
+			host := &mock.RepositoryClient{
+				LastReleaseFn: func() (*ergo.Release, error) {
+					return release, nil
+				},
+				UpdateBranchFromTagFn: func() error {
+					updated++
+					return nil
+				},
+			}
+			deploy := &Deploy{
+				c:               &mock.CLI{},
+				host:            host,
+				time:            mock.NewMockedTime(now),
+				releaseBranches: []string{"release-gr", "release-mx", "release-pe"},
+			}
+			deploy.SetStartTime(now)
+			freeze := NewFreeze(&FreezeCalendar{Windows: []FreezeWindow{tt.window}}, nil)
+			freeze.SetOverride(tt.override)
+			deploy.SetFreeze(freeze)
+
+			err := deploy.Do(ctx, "30m", "1m", false, true, false)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "--override-freeze") {
+					t.Errorf("expected the freeze error, got %v", err)
+				}
+				if updated != 0 {
+					t.Errorf("expected no branch to be deployed, got %d", updated)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Do should not return the error: %v", err)
+			}
+			overridden := strings.Contains(release.Body, "Deploy freeze overridden by deploy")
+			if name == "overridden" && (!overridden || !strings.Contains(release.Body, tt.override)) {
+				t.Errorf("expected the override to be recorded in the release body, got %q", release.Body)
+			}
+			if name != "overridden" && overridden {
+				t.Errorf("expected no override in the release body, got %q", release.Body)
+			}
+		})
+	}
+}
+
+func TestPrintReleaseTimeBoardShouldPrintTheBoardTimeZones(t *testing.T) {
+	athens, errAthens := time.LoadLocation("Europe/Athens")
+	mexico, errMexico := time.LoadLocation("America/Mexico_City")
+	if errAthens != nil || errMexico != nil {
+		t.Skip("time zone database not available")
+	}
+	cliMock := &mock.CLI{}
+	deploy := &Deploy{c: cliMock}
+	deploy.SetBoardLocations([]*time.Location{athens, mexico})
+
+	start := time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC)
+	deploy.printReleaseTimeBoard(start, []string{"release-gr", "release-mx"}, []time.Duration{time.Hour})
+
+	call := cliMock.PrintTableCalls[0]
+	if want := []string{"Branch", "Europe/Athens", "America/Mexico_City"}; !reflect.DeepEqual(call.Header, want) {
+		t.Errorf("expected the headers %v, got %v", want, call.Header)
+	}
+	want := [][]string{
+		{"release-gr", "Tue 20 Oct 08:00 EEST", "Mon 19 Oct 23:00 CST"},
+		{"release-mx", "Tue 20 Oct 09:00 EEST", "Tue 20 Oct 00:00 CST"},
+	}
+	if !reflect.DeepEqual(call.Values, want) {
+		t.Errorf("expected the rows %v, got %v", want, call.Values)
+	}
+}
+
+func TestPrintReleaseTimeBoardShouldPrintTheScheduleTimeZone(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	cliMock := &mock.CLI{}
+	deploy := &Deploy{c: cliMock}
+	deploy.SetScheduleLocation(athens)
+
+	start := time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC)
+	deploy.printReleaseTimeBoard(start, []string{"release-gr"}, []time.Duration{time.Hour})
+
+	call := cliMock.PrintTableCalls[0]
+	if want := []string{"Branch", "Start Time"}; !reflect.DeepEqual(call.Header, want) {
+		t.Errorf("expected the headers %v, got %v", want, call.Header)
+	}
+	if want := [][]string{{"release-gr", "08:00 EEST"}}; !reflect.DeepEqual(call.Values, want) {
+		t.Errorf("expected the rows %v, got %v", want, call.Values)
+	}
+}
@@ -73,7 +73,7 @@ func TestCreateShouldReturnError(t *testing.T) {
 func TestExistsTagNameShouldReturnTrue(t *testing.T) {
 	host := &mock.RepositoryClient{}
 
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 
@@ -102,7 +102,7 @@ func TestExistsTagNameShouldReturnFalse(t *testing.T) {
 
 func TestExistsTagNameShouldReturnError(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return nil, errors.New("")
 	}
 
@@ -0,0 +1,90 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/beatlabs/ergo"
+)
+
+// BodyModel is the data of a release body template.
+type BodyModel struct {
+	Version    string
+	BaseBranch string
+	// Prefix is the configured release body prefix.
+	Prefix   string
+	Branches []*BodyBranch
+	// Commits are the commits of the base branch which are not deployed yet.
+	Commits []*ergo.Commit
+	// PullRequests are the merged pull requests of the commits and Sections the pull requests
+	// grouped by label, only set when drafting from pull requests.
+	PullRequests []*ergo.PullRequest
+	Sections     []*BodySection
+	// BreakingChanges are the conventional commits with breaking changes and CommitSections the
+	// commits grouped by type, only set when drafting from conventional commits.
+	BreakingChanges []*ConventionalCommit
+	CommitSections  []*CommitSection
+}
+
+// otherSection is the title of the section of the pull requests without a mapped label and the
+// commits without a pull request, or of the commits which are not conventional.
+const otherSection = "### Other"
+
+// BodySection is a group of pull requests of the release body template.
+type BodySection struct {
+	Title        string
+	PullRequests []*ergo.PullRequest
+	// Commits are the commits without a merged pull request, only set in the other section.
+	Commits []*ergo.Commit
+}
+
+// BodyBranch is a release branch of the release body template.
+type BodyBranch struct {
+	Name        string
+	DisplayName string
+	// Marker is the badge which deploy replaces once the release is deployed to the branch, so it
+	// has to be part of the release body for the deployment to be tracked.
+	Marker string
+}
+
+// Markers returns the markers of all the branches separated by spaces.
+func (m *BodyModel) Markers() string {
+	markers := make([]string, 0, len(m.Branches))
+	for _, b := range m.Branches {
+		markers = append(markers, b.Marker)
+	}
+	return strings.Join(markers, " ")
+}
+
+// bodyTemplateFuncs are the functions available to release body templates.
+var bodyTemplateFuncs = template.FuncMap{
+	"firstLine": func(s string) string {
+		return strings.TrimSpace(strings.SplitN(strings.TrimSpace(s), "\n", 2)[0])
+	},
+	"shortSHA": func(sha string) string {
+		if len(sha) > 7 {
+			return sha[:7]
+		}
+		return sha
+	},
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n"+pad)
+	},
+}
+
+// ParseBodyTemplate parses a text/template of the release body. Besides the model, the template
+// can use the functions firstLine, shortSHA and indent.
+func ParseBodyTemplate(text string) (*template.Template, error) {
+	t, err := template.New("release-body").Funcs(bodyTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release body template: %w", err)
+	}
+	return t, nil
+}
+
+// bodyMarker returns the badge of a branch the release has not been deployed to.
+func bodyMarker(branchText string) string {
+	return fmt.Sprintf("%s ![](https://img.shields.io/badge/released-No-red.svg)", branchText)
+}
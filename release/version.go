@@ -4,22 +4,32 @@ import (
 	"context"
 
 	"github.com/beatlabs/ergo"
+	ergoTime "github.com/beatlabs/ergo/time"
 	"github.com/blang/semver"
 	"github.com/hashicorp/go-version"
 )
 
 // Version is responsible to describe the actions of visioning.
 type Version struct {
-	host       ergo.Host
-	baseBranch string
+	host         ergo.Host
+	baseBranch   string
+	calVerFormat string
+	time         ergo.Time
 }
 
 // NewVersion initializes and return a new Version object.
 func NewVersion(host ergo.Host, baseBranch string) *Version {
-	return &Version{host: host, baseBranch: baseBranch}
+	return &Version{host: host, baseBranch: baseBranch, time: ergoTime.Time{}}
 }
 
-// NextVersion finds the next version according to major/minor/patch pattern.
+// NewCalVersion initializes and return a new Version object which uses calendar versioning
+// with the given format, e.g. YYYY.0M.MICRO.
+func NewCalVersion(host ergo.Host, baseBranch, calVerFormat string) *Version {
+	return &Version{host: host, baseBranch: baseBranch, calVerFormat: calVerFormat, time: ergoTime.Time{}}
+}
+
+// NextVersion finds the next version according to major/minor/patch pattern, or the calendar
+// version format if one is set. The major and minor flags are ignored for calendar versions.
 func (v Version) NextVersion(ctx context.Context, inputVersion, suffix string, major, minor bool) (*ergo.Version, error) {
 	baseBranchReference, err := v.host.GetRef(ctx, v.baseBranch)
 	if err != nil {
@@ -31,6 +41,14 @@ func (v Version) NextVersion(ctx context.Context, inputVersion, suffix string, m
 		return &ergo.Version{Name: forceVersion, SHA: baseBranchReference.SHA}, nil
 	}
 
+	if v.calVerFormat != "" {
+		name, errCalVer := v.nextCalVersion(ctx, suffix)
+		if errCalVer != nil {
+			return nil, errCalVer
+		}
+		return &ergo.Version{Name: name, SHA: baseBranchReference.SHA}, nil
+	}
+
 	// Calculate the new version name according to remote tags names.
 	lastRelease, err := v.host.LastRelease(ctx)
 	if err != nil {
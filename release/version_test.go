@@ -17,7 +17,7 @@ func TestNewVersionShouldNotReturnNilObject(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithDefaultParameters(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -42,13 +42,13 @@ func TestNextVersionShouldReturnTheNextVersionWithDefaultParameters(t *testing.T
 
 func TestNextVersionShouldReturnTheNextVersionWithSuffixSameSHA(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
 		return &ergo.Release{TagName: "1.0.0"}, nil
 	}
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 
@@ -70,13 +70,13 @@ func TestNextVersionShouldReturnTheNextVersionWithSuffixSameSHA(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithSuffixDifferentSHA(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha1", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
 		return &ergo.Release{TagName: "1.0.0"}, nil
 	}
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha2", Ref: "ref"}, nil
 	}
 
@@ -98,7 +98,7 @@ func TestNextVersionShouldReturnTheNextVersionWithSuffixDifferentSHA(t *testing.
 
 func TestNextVersionShouldReturnTheNextVersionWithInputVersion(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 
@@ -120,7 +120,7 @@ func TestNextVersionShouldReturnTheNextVersionWithInputVersion(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithInputVersionAndSuffix(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 
@@ -142,7 +142,7 @@ func TestNextVersionShouldReturnTheNextVersionWithInputVersionAndSuffix(t *testi
 
 func TestNextVersionShouldReturnTheNextVersionWithCustomLastReleaseVersion(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -167,7 +167,7 @@ func TestNextVersionShouldReturnTheNextVersionWithCustomLastReleaseVersion(t *te
 
 func TestNextVersionShouldReturnTheNextVersionWithMinorFlag(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -192,7 +192,7 @@ func TestNextVersionShouldReturnTheNextVersionWithMinorFlag(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithMajorFlag(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -217,13 +217,13 @@ func TestNextVersionShouldReturnTheNextVersionWithMajorFlag(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithSuffixAndMajor(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha1", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
 		return &ergo.Release{TagName: "1.0.0"}, nil
 	}
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha2", Ref: "ref"}, nil
 	}
 
@@ -245,7 +245,7 @@ func TestNextVersionShouldReturnTheNextVersionWithSuffixAndMajor(t *testing.T) {
 
 func TestNextVersionShouldReturnTheNextVersionWithMajorAndMinor(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -270,7 +270,7 @@ func TestNextVersionShouldReturnTheNextVersionWithMajorAndMinor(t *testing.T) {
 
 func TestNextVersionShouldReturnDefaultVersionWhenNoReleases(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -295,7 +295,7 @@ func TestNextVersionShouldReturnDefaultVersionWhenNoReleases(t *testing.T) {
 
 func TestNextVersionShouldReturnErrorOnGetRef(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return nil, errors.New("")
 	}
 
@@ -308,7 +308,7 @@ func TestNextVersionShouldReturnErrorOnGetRef(t *testing.T) {
 
 func TestNextVersionShouldReturnErrorOnLastRelease(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -324,7 +324,7 @@ func TestNextVersionShouldReturnErrorOnLastRelease(t *testing.T) {
 
 func TestNextVersionShouldReturnDefaultVersionOnWrongFormat(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
@@ -349,13 +349,13 @@ func TestNextVersionShouldReturnDefaultVersionOnWrongFormat(t *testing.T) {
 
 func TestNextVersionShouldReturnErrorOnLastReleaseWithSuffix(t *testing.T) {
 	host := &mock.RepositoryClient{}
-	host.GetRefFn = func() (*ergo.Reference, error) {
+	host.GetRefFn = func(string) (*ergo.Reference, error) {
 		return &ergo.Reference{SHA: "sha", Ref: "ref"}, nil
 	}
 	host.LastReleaseFn = func() (*ergo.Release, error) {
 		return &ergo.Release{TagName: "1.0.0"}, nil
 	}
-	host.GetRefFromTagFn = func() (*ergo.Reference, error) {
+	host.GetRefFromTagFn = func(string) (*ergo.Reference, error) {
 		return nil, errors.New("")
 	}
 
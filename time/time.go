@@ -1,6 +1,9 @@
 package time
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Time struct{}
 
@@ -11,3 +14,46 @@ func (w Time) Sleep(duration time.Duration) {
 func (w Time) Now() time.Time {
 	return time.Now()
 }
+
+// Virtual is a clock which only advances when sleeping, so that a schedule runs instantly.
+type Virtual struct {
+	now time.Time
+}
+
+// NewVirtual creates a virtual clock starting at the time.
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{now: start}
+}
+
+// Sleep advances the clock by the duration without waiting.
+func (v *Virtual) Sleep(duration time.Duration) {
+	if duration > 0 {
+		v.now = v.now.Add(duration)
+	}
+}
+
+// Now returns the time of the clock.
+func (v *Virtual) Now() time.Time {
+	return v.now
+}
+
+// wallClockLayouts are the layouts of the wall clock times, without a zone, accepted by
+// ParseWallClock.
+var wallClockLayouts = []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// ParseWallClock parses a wall clock time such as "2026-10-20T14:00" in the location, or a
+// RFC 3339 time with its own offset. The local time zone is used if the location is nil.
+func ParseWallClock(value string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range wallClockLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected e.g. 2026-10-20T14:00", value)
+}